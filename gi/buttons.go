@@ -255,6 +255,7 @@ func (bb *ButtonBase) SetButtonState(state ButtonStates) bool {
 	bb.State = state
 	bb.StyMu.Lock()
 	bb.Sty = bb.StateStyles[state]
+	bb.LayState.BoxSpcOk = false // style replaced
 	bb.StyMu.Unlock()
 	if prev != bb.State {
 		bb.SetFullReRenderIconLabel() // needs full rerender to update text, icon
@@ -287,6 +288,7 @@ func (bb *ButtonBase) UpdateButtonStyle() bool {
 		}
 	}
 	bb.Sty = bb.StateStyles[bb.State]
+	bb.LayState.BoxSpcOk = false // style replaced
 	bb.This().(ButtonWidget).ConfigPartsIfNeeded()
 	if prev != bb.State {
 		bb.SetFullReRenderIconLabel() // needs full rerender
@@ -127,6 +127,59 @@ func (wl *WinEventRecvList) AddDepth(recv ki.Ki, fun ki.RecvFunc, par ki.Ki) {
 	wl.Add(recv, fun, recv.ParentLevel(par))
 }
 
+// PaintsAfter returns true if node a is painted after (i.e., on top of)
+// node b, where both are at the same depth in the tree -- determined by
+// the paint order of their ancestors within their closest common parent
+// (see Layout.PaintKids).  Use a PaintOrder to compare many nodes.
+func PaintsAfter(a, b ki.Ki) bool {
+	return PaintOrder{}.PaintsAfter(a, b)
+}
+
+// PaintIndex returns the index of given node in the paint order of its
+// parent: its position in the PaintKids of a parent Layout, and otherwise
+// its index in the parent.
+func PaintIndex(k ki.Ki) int {
+	return PaintOrder{}.Index(k)
+}
+
+// PaintOrder records the PaintIndex of nodes, so the paint order of each
+// parent is only computed once when comparing many nodes, e.g., in sorting
+// the receivers of an event -- it must not be kept across changes to the
+// tree or styles.
+type PaintOrder map[ki.Ki]int
+
+// Index returns the PaintIndex of given node, recording it and those of
+// its siblings the first time.
+func (po PaintOrder) Index(k ki.Ki) int {
+	if idx, ok := po[k]; ok {
+		return idx
+	}
+	if par := k.Parent(); par != nil {
+		if ly, ok := par.Embed(KiT_Layout).(*Layout); ok && ly != nil {
+			for i, kid := range ly.PaintKids() {
+				po[kid] = i
+			}
+			if idx, ok := po[k]; ok {
+				return idx
+			}
+		}
+	}
+	idx, _ := k.IndexInParent()
+	po[k] = idx
+	return idx
+}
+
+// PaintsAfter is PaintsAfter using the recorded paint indexes.
+func (po PaintOrder) PaintsAfter(a, b ki.Ki) bool {
+	for a.Parent() != b.Parent() {
+		if a.Parent() == nil || b.Parent() == nil {
+			return false
+		}
+		a, b = a.Parent(), b.Parent()
+	}
+	return po.Index(a) > po.Index(b)
+}
+
 // ConnectEvent adds a Signal connection for given event type and
 // priority to given receiver
 func (em *EventMgr) ConnectEvent(recv ki.Ki, et oswin.EventType, pri EventPris, fun ki.RecvFunc) {
@@ -209,8 +262,12 @@ func (em *EventMgr) SendEventSignal(evi oswin.Event, popup bool) {
 			continue
 		}
 
-		// deepest first
+		// deepest first, and topmost in paint order among equal depths
+		po := PaintOrder{}
 		sort.Slice(rvs, func(i, j int) bool {
+			if rvs[i].Data == rvs[j].Data {
+				return po.PaintsAfter(rvs[i].Recv, rvs[j].Recv)
+			}
 			return rvs[i].Data > rvs[j].Data
 		})
 
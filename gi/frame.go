@@ -6,6 +6,7 @@ package gi
 
 import (
 	"image"
+	"image/draw"
 	"log"
 
 	"github.com/goki/gi/gist"
@@ -19,7 +20,10 @@ import (
 // background-color style setting, and optional striping for grid layouts
 type Frame struct {
 	Layout
-	Stripes Stripes `desc:"options for striped backgrounds -- rendered as darker bands relative to background color"`
+	Stripes      Stripes         `desc:"options for striped backgrounds -- rendered as darker bands relative to background color"`
+	ClipToRadius bool            `desc:"clip the rendering of the frame and its children to the rounded corners of the border, when the border-radius is > 0 -- otherwise children are only clipped to the rectangular bounding box"`
+	ClipSave     []*image.RGBA   `copy:"-" json:"-" xml:"-" view:"-" desc:"pixels within the rounded corners, saved by PushBounds when ClipToRadius is set, and restored outside of the corners by PopBounds"`
+	ShadowVpBBox image.Rectangle `copy:"-" json:"-" xml:"-" view:"-" desc:"viewport-relative bounding box of the box shadow, computed along with the VpBBox, clipped to the parent -- it extends beyond the VpBBox where the shadow offsets exceed the margin, and is empty if there is no visible shadow"`
 }
 
 var KiT_Frame = kit.Types.AddType(&Frame{}, FrameProps)
@@ -40,6 +44,7 @@ func (fr *Frame) CopyFieldsFrom(frm interface{}) {
 	}
 	fr.Layout.CopyFieldsFrom(&cp.Layout)
 	fr.Stripes = cp.Stripes
+	fr.ClipToRadius = cp.ClipToRadius
 }
 
 var FrameProps = ki.Props{
@@ -70,31 +75,87 @@ var KiT_Stripes = kit.Enums.AddEnumAltLower(StripesN, kit.NotBitFlag, gist.Style
 func (ev Stripes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *Stripes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
-// FrameStdRender does the standard rendering of the frame itself
+// BgBox returns the position and size of the background box of the frame,
+// which is the allocated box minus the margin on each side -- the background
+// thus follows the allocated size in each dimension separately (e.g., only
+// stretching horizontally), and never draws into the margin area.
+func (fr *Frame) BgBox() (pos, sz mat32.Vec2) {
+	mrg := fr.Sty.Layout.Margin.Dots
+	pos = fr.LayState.Alloc.Pos.AddScalar(mrg)
+	sz = fr.LayState.Alloc.Size.SubScalar(2.0 * mrg).Max(mat32.Vec2Zero)
+	return
+}
+
+// ShadowBox returns the position and size of the box shadow of the frame:
+// the background box, including the border, offset by the shadow offsets.
+func (fr *Frame) ShadowBox() (pos, sz mat32.Vec2) {
+	st := &fr.Sty
+	pos, sz = fr.BgBox()
+	pos = pos.SubScalar(0.5 * st.Border.Width.Dots)
+	sz = sz.AddScalar(st.Border.Width.Dots)
+	pos = pos.Add(mat32.NewVec2(st.BoxShadow.HOffset.Dots, st.BoxShadow.VOffset.Dots))
+	return
+}
+
+func (fr *Frame) ComputeBBox2D(parBBox image.Rectangle, delta image.Point) {
+	fr.Layout.ComputeBBox2D(parBBox, delta)
+	fr.BBoxMu.Lock()
+	fr.ShadowVpBBox = image.ZR
+	if fr.Sty.BoxShadow.HasShadow() { // alloc pos already includes delta
+		fr.ShadowVpBBox = parBBox.Intersect(LayoutRect(fr.ShadowBox()))
+	}
+	fr.BBoxMu.Unlock()
+}
+
+// Shadower is implemented by nodes that draw a box shadow outside of their
+// VpBBox -- the shadow is included in the region uploaded when they are
+// re-rendered on their own, see ReRender2DBBoxes
+type Shadower interface {
+	// ShadowBBox2D returns the viewport-relative bounding box of the
+	// shadow, empty if none
+	ShadowBBox2D() image.Rectangle
+}
+
+// ShadowBBox2D returns the ShadowVpBBox, under the BBoxMu -- see Shadower
+func (fr *Frame) ShadowBBox2D() image.Rectangle {
+	fr.BBoxMu.RLock()
+	defer fr.BBoxMu.RUnlock()
+	return fr.ShadowVpBBox
+}
+
+// RenderShadow draws the box shadow of the frame, if any -- called prior to
+// PushBounds, so the shadow is only clipped to the bounds of the parent,
+// and not cut off at the edge of the frame where it extends beyond the
+// margin.  The background is drawn over it by FrameStdRender.
+func (fr *Frame) RenderShadow() {
+	if fr.ShadowVpBBox == image.ZR {
+		return
+	}
+	rs, pc, st := fr.RenderLock()
+	defer fr.RenderUnlock(rs)
+	pos, sz := fr.ShadowBox()
+	pc.StrokeStyle.SetColor(nil)
+	pc.FillStyle.SetColor(&st.BoxShadow.Color)
+	if rad := st.Border.Radius.Dots; rad == 0.0 {
+		pc.DrawRectangle(rs, pos.X, pos.Y, sz.X, sz.Y)
+	} else {
+		pc.DrawRoundedRectangle(rs, pos.X, pos.Y, sz.X, sz.Y, rad)
+	}
+	pc.FillStrokeClear(rs)
+}
+
+// FrameStdRender does the standard rendering of the frame itself -- the
+// box shadow is drawn separately, before PushBounds (see RenderShadow)
 func (fr *Frame) FrameStdRender() {
 	rs, pc, st := fr.RenderLock()
 	defer fr.RenderUnlock(rs)
 
-	pos := fr.LayState.Alloc.Pos
-	sz := fr.LayState.Alloc.Size
+	pos, sz := fr.BgBox()
 	pc.FillBox(rs, pos, sz, &st.Font.BgColor)
 
 	rad := st.Border.Radius.Dots
-	pos = pos.AddScalar(st.Layout.Margin.Dots).SubScalar(0.5 * st.Border.Width.Dots)
-	sz = sz.SubScalar(2.0 * st.Layout.Margin.Dots).AddScalar(st.Border.Width.Dots)
-
-	// then any shadow -- todo: optimize!
-	if st.BoxShadow.HasShadow() {
-		spos := pos.Add(mat32.Vec2{st.BoxShadow.HOffset.Dots, st.BoxShadow.VOffset.Dots})
-		pc.StrokeStyle.SetColor(nil)
-		pc.FillStyle.SetColor(&st.BoxShadow.Color)
-		if rad == 0.0 {
-			pc.DrawRectangle(rs, spos.X, spos.Y, sz.X, sz.Y)
-		} else {
-			pc.DrawRoundedRectangle(rs, spos.X, spos.Y, sz.X, sz.Y, rad)
-		}
-		pc.FillStrokeClear(rs)
-	}
+	pos = pos.SubScalar(0.5 * st.Border.Width.Dots)
+	sz = sz.AddScalar(st.Border.Width.Dots)
 
 	if fr.Lay == LayoutGrid && fr.Stripes != NoStripes {
 		fr.RenderStripes()
@@ -118,6 +179,7 @@ func (fr *Frame) RenderStripes() {
 
 	pos := fr.LayState.Alloc.Pos
 	sz := fr.LayState.Alloc.Size
+	bpos, bsz := fr.BgBox() // stripes span the background box only
 
 	delta := fr.Move2DDelta(image.ZP)
 
@@ -132,9 +194,9 @@ func (fr *Frame) RenderStripes() {
 			if pry+szy < 0 || pry > sz.Y {
 				continue
 			}
-			pr := pos
-			pr.Y += pry
-			sr := sz
+			pr := bpos
+			pr.Y = pos.Y + pry
+			sr := bsz
 			sr.Y = szy
 			pc.FillBoxColor(rs, pr, sr, hic)
 		}
@@ -148,24 +210,96 @@ func (fr *Frame) RenderStripes() {
 			if prx+szx < 0 || prx > sz.X {
 				continue
 			}
-			pr := pos
-			pr.X += prx
-			sr := sz
+			pr := bpos
+			pr.X = pos.X + prx
+			sr := bsz
 			sr.X = szx
 			pc.FillBoxColor(rs, pr, sr, hic)
 		}
 	}
 }
 
+// RoundedClip returns the box and radius of the rounded clip of the frame
+// when ClipToRadius is set: the outer edge of the border, with its corners
+// rounded to match -- rad is 0 if there is no rounded clip.
+func (fr *Frame) RoundedClip() (pos, sz mat32.Vec2, rad float32) {
+	if !fr.ClipToRadius || fr.Sty.Border.Radius.Dots <= 0 {
+		return
+	}
+	hbw := 0.5 * fr.Sty.Border.Width.Dots
+	pos, sz = fr.BgBox()
+	pos = pos.SubScalar(hbw)
+	sz = sz.AddScalar(2 * hbw)
+	rad = fr.Sty.Border.Radius.Dots + hbw
+	return
+}
+
+// PushBounds pushes our bounding-box bounds, and the rounded clip of the
+// frame, if ClipToRadius is set (see RoundedClip): the pixels within each
+// rounded corner are saved prior to rendering the frame and its children,
+// and restored outside of the corner by PopBounds, with antialiasing.
+func (fr *Frame) PushBounds() bool {
+	if !fr.Layout.PushBounds() {
+		return false
+	}
+	pos, sz, rad := fr.RoundedClip()
+	if rad <= 0 {
+		return true
+	}
+	rs := &fr.Viewport.Render
+	rs.Lock()
+	defer rs.Unlock()
+	rsz := mat32.NewVec2(rad, rad)
+	far := pos.Add(sz).Sub(rsz)
+	for _, cp := range []mat32.Vec2{pos, mat32.NewVec2(far.X, pos.Y), mat32.NewVec2(pos.X, far.Y), far} {
+		bb := LayoutRect(cp, rsz).Intersect(rs.Bounds)
+		if bb.Empty() {
+			continue
+		}
+		sv := image.NewRGBA(bb)
+		draw.Draw(sv, bb, rs.Image, bb.Min, draw.Src)
+		fr.ClipSave = append(fr.ClipSave, sv)
+	}
+	return true
+}
+
+// PopBounds restores the pixels saved by PushBounds outside of the rounded
+// clip, blending them by the coverage of the clip at its edge, and pops our
+// bounding-box bounds.
+func (fr *Frame) PopBounds() {
+	if len(fr.ClipSave) > 0 && fr.Viewport != nil {
+		pos, sz, rad := fr.RoundedClip()
+		mn := pos.AddScalar(rad) // centers of the corner circles
+		mx := pos.Add(sz).SubScalar(rad)
+		rs := &fr.Viewport.Render
+		rs.Lock()
+		for _, sv := range fr.ClipSave {
+			bb := sv.Bounds()
+			out := image.NewAlpha(bb) // coverage outside of the clip
+			for y := bb.Min.Y; y < bb.Max.Y; y++ {
+				for x := bb.Min.X; x < bb.Max.X; x++ {
+					p := mat32.NewVec2(float32(x)+0.5, float32(y)+0.5)
+					d := p.DistTo(p.Max(mn).Min(mx)) - rad + 0.5
+					out.Pix[out.PixOffset(x, y)] = uint8(255 * mat32.Clamp(d, 0, 1))
+				}
+			}
+			draw.DrawMask(rs.Image, bb, sv, bb.Min, out, bb.Min, draw.Src)
+		}
+		rs.Unlock()
+	}
+	fr.ClipSave = fr.ClipSave[:0]
+	fr.Layout.PopBounds()
+}
+
 func (fr *Frame) Render2D() {
 	if fr.FullReRenderIfNeeded() {
 		return
 	}
+	fr.RenderShadow()
 	if fr.PushBounds() {
 		fr.FrameStdRender()
 		fr.This().(Node2D).ConnectEvents2D()
-		fr.RenderScrolls()
-		fr.Render2DChildren()
+		fr.RenderContent()
 		fr.PopBounds()
 	} else {
 		fr.SetScrollsOff()
@@ -135,7 +135,7 @@ func (lb *Label) SetText(txt string) {
 	} else {
 		lb.Render.SetHTML(lb.Text, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
 	}
-	spc := lb.BoxSpace()
+	spc := lb.Sty.BoxSpace() // style is locked
 	sz := lb.LayState.Alloc.Size
 	if sz.IsNil() {
 		sz = lb.LayState.SizePrefOrMax()
@@ -164,6 +164,7 @@ func (lb *Label) SetStateStyle() {
 			lb.Sty.Font.BgColor.SetColor(lb.CurBgColor)
 		}
 	}
+	lb.LayState.BoxSpcOk = false // style replaced
 	lb.StyMu.Unlock()
 }
 
@@ -336,7 +337,7 @@ func (lb *Label) LayoutLabel() {
 
 	lb.Sty.Font.BgColor.Color.SetToNil() // always use transparent bg for actual text
 	lb.Render.SetHTML(lb.Text, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
-	spc := lb.BoxSpace()
+	spc := lb.Sty.BoxSpace() // style is locked
 	sz := lb.LayState.SizePrefOrMax()
 	if !sz.IsNil() {
 		sz.SetSubScalar(2 * spc)
@@ -391,6 +392,18 @@ func (lb *Label) TextPos() mat32.Vec2 {
 	return pos
 }
 
+// BaselineOffset returns the offset of the baseline of the first line of
+// text from the top of the label
+func (lb *Label) BaselineOffset() float32 {
+	lb.StyMu.RLock()
+	defer lb.StyMu.RUnlock()
+	off := lb.Sty.BoxSpace()
+	if len(lb.Render.Spans) > 0 {
+		off += lb.Render.Spans[0].RelPos.Y
+	}
+	return off
+}
+
 func (lb *Label) RenderLabel() {
 	lb.GrabCurBgColor()
 	lb.SetStateStyle()
@@ -7,11 +7,15 @@ package gi
 import (
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"log"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
+	"github.com/goki/gi/girl"
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/dnd"
@@ -32,6 +36,11 @@ var LayoutPrefMaxRows = 20
 // when computing the preferred size (VpFlagPrefSizing)
 var LayoutPrefMaxCols = 20
 
+// LayoutEqualTol is the tolerance in dots within which allocated positions
+// and sizes are considered equal by LayoutAllocs.Equals -- absorbs the
+// floating point jitter between otherwise identical layout passes
+var LayoutEqualTol = float32(0.01)
+
 // LayoutAllocs contains all the the layout allocations: size, position.
 // These are set by the parent Layout during the Layout process.
 type LayoutAllocs struct {
@@ -42,6 +51,12 @@ type LayoutAllocs struct {
 	PosOrig  mat32.Vec2 `desc:"original copy of allocated relative position of this item, by the parent layout -- need for scrolling which can update AllocPos"`
 }
 
+// Equals returns true if the allocated position and size are the same as
+// those of the other allocation, within LayoutEqualTol
+func (la *LayoutAllocs) Equals(ol *LayoutAllocs) bool {
+	return la.Pos.AlmostEqual(ol.Pos, LayoutEqualTol) && la.Size.AlmostEqual(ol.Size, LayoutEqualTol)
+}
+
 // Reset is called at start of layout process -- resets all values back to 0
 func (la *LayoutAllocs) Reset() {
 	la.Size = mat32.Vec2Zero
@@ -52,8 +67,17 @@ func (la *LayoutAllocs) Reset() {
 // LayoutState contains all the state needed to specify the layout of an item
 // within a Layout.  Is initialized with computed values of style prefs.
 type LayoutState struct {
-	Size  gist.SizePrefs `desc:"size constraints for this item -- set from layout style at start of layout process and then updated for Layout nodes to fit everything within it"`
-	Alloc LayoutAllocs   `desc:"allocated size and position -- set by parent Layout"`
+	Size         gist.SizePrefs `desc:"size constraints for this item -- set from layout style at start of layout process and then updated for Layout nodes to fit everything within it"`
+	Alloc        LayoutAllocs   `desc:"allocated size and position -- set by parent Layout"`
+	Prev         LayoutAllocs   `desc:"allocation from the previous layout pass -- saved when the state is first reset at the start of a pass, so Alloc can be compared against it to determine what changed"`
+	Stretch      mat32.Vec2     `desc:"relative weight for stretching this item within its layout, from a width / height specified in Fr units -- 0 means stretch in proportion to Pref size"`
+	Grow         float32        `desc:"flex-grow factor from the style: if > 0, the item grows beyond its Pref size with this weight when its layout has extra space, up to its Max size if set"`
+	Shrink       float32        `desc:"flex-shrink factor from the style: if > 0, the item shrinks below its Pref size, down to its Need size, in proportion to this factor times its Pref size when its layout does not have room for the Pref sizes"`
+	BoxSpc       float32        `desc:"cached box space (margin + border + padding) from the style -- computed on first use after a Reset, which happens at the start of each Size2D and Style2D pass -- see WidgetBase.BoxSpace"`
+	BoxSpcOk     bool           `desc:"true if BoxSpc has been computed since the last Reset"`
+	UnitsChanged bool           `desc:"true if the size constraints from the style changed when its units were updated with the final layout sizes in Layout2D (e.g., percentages of the parent size), so the sizes used in this layout pass are out of date -- cleared by Reset"`
+	ParSize      mat32.Vec2     `desc:"allocated size of the parent in the last Layout2D pass -- used as the element-relative unit context when styling (e.g., for percentages), so the sizes re-resolved in Layout2D drive the next pass -- not cleared by Reset"`
+	AspectH      float32        `desc:"height computed from the aspect-ratio style and the allocated width in the last Layout2D pass -- used as the Need and Pref height when sizing, so the layout makes room for it -- not cleared by Reset"`
 }
 
 // todo: not using yet:
@@ -70,6 +94,22 @@ func (ld *LayoutState) SetFromStyle(ls *gist.Layout) {
 	ld.Size.Need = ls.MinSizeDots()
 	ld.Size.Pref = ls.SizeDots()
 	ld.Size.Max = ls.MaxSizeDots()
+	ld.Stretch = mat32.Vec2Zero
+	// fr units are stretchy with given weight
+	if ls.Width.Un == units.Fr {
+		ld.Size.Max.X = -1
+		ld.Stretch.X = ls.Width.Val
+	}
+	if ls.Height.Un == units.Fr {
+		ld.Size.Max.Y = -1
+		ld.Stretch.Y = ls.Height.Val
+	}
+	ld.Grow = ls.FlexGrow
+	ld.Shrink = ls.FlexShrink
+	if ls.AspectRatio > 0 && ld.AspectH > 0 { // height from the width, see LayoutAspect
+		ld.Size.Need.Y = ld.AspectH
+		ld.Size.Pref.Y = ld.AspectH
+	}
 
 	// this is an actual initial desired setting
 	ld.Alloc.Pos = ls.PosDots()
@@ -82,9 +122,66 @@ func (ld *LayoutState) SizePrefOrMax() mat32.Vec2 {
 	return ld.Size.Pref.MinPos(ld.Size.Max)
 }
 
+// StretchWeight returns the relative weight for stretching along given
+// dimension: the Stretch weight if set, else the Grow factor if set, else
+// the Pref size
+func (ld *LayoutState) StretchWeight(d mat32.Dims) float32 {
+	if sw := ld.Stretch.Dim(d); sw > 0 {
+		return sw
+	}
+	if ld.Grow > 0 {
+		return ld.Grow
+	}
+	return ld.Size.Pref.Dim(d)
+}
+
+// CanGrow returns true if the item can grow beyond its Pref size along
+// given dimension, when its layout has extra space: if it is stretchy
+// (Max < 0) or has a Grow factor
+func (ld *LayoutState) CanGrow(d mat32.Dims) bool {
+	return ld.Size.HasMaxStretch(d) || ld.Grow > 0
+}
+
+// CanStretchNeed returns true if the item can stretch beyond its Need size
+// along given dimension, when its layout only fits the Need sizes of its
+// children: if its Pref is larger than its Need and it has no Shrink factor
+// -- a shrinking item is then already at its Need size
+func (ld *LayoutState) CanStretchNeed(d mat32.Dims) bool {
+	return ld.Size.CanStretchNeed(d) && ld.Shrink <= 0
+}
+
+// GrowLimit returns the max amount that the item can grow beyond given base
+// size along given dimension: the Max size less the base, for a Grow item
+// with a Max size -- -1 if unlimited
+func (ld *LayoutState) GrowLimit(d mat32.Dims, base float32) float32 {
+	mx := ld.Size.Max.Dim(d)
+	if ld.Grow <= 0 || mx <= 0 {
+		return -1
+	}
+	return mat32.Max(mx-base, 0)
+}
+
+// Equals returns true if the allocation of this state is the same as that
+// of the other state, within LayoutEqualTol
+func (ld *LayoutState) Equals(ost *LayoutState) bool {
+	return ld.Alloc.Equals(&ost.Alloc)
+}
+
+// AllocChanged returns true if the current allocation differs from the
+// one in the previous layout pass (Prev) -- e.g., to only re-render
+// the children whose layout actually changed
+func (ld *LayoutState) AllocChanged() bool {
+	return !ld.Alloc.Equals(&ld.Prev)
+}
+
 // Reset is called at start of layout process -- resets all values back to 0
 func (ld *LayoutState) Reset() {
+	if ld.Alloc.Size != mat32.Vec2Zero { // not yet reset in this pass
+		ld.Prev = ld.Alloc
+	}
 	ld.Alloc.Reset()
+	ld.BoxSpcOk = false
+	ld.UnitsChanged = false
 }
 
 // UpdateSizes updates our sizes based on AllocSize and Max constraints, etc
@@ -100,10 +197,142 @@ type GridData struct {
 	SizeNeed    float32
 	SizePref    float32
 	SizeMax     float32
+	Stretch     float32
 	AllocSize   float32
 	AllocPosRel float32
 }
 
+// StretchWeight returns the relative weight for stretching this track: the
+// Stretch weight if set, from an fr max size of its GridTrack, else the
+// Pref size
+func (gd *GridData) StretchWeight() float32 {
+	if gd.Stretch > 0 {
+		return gd.Stretch
+	}
+	return gd.SizePref
+}
+
+// GridCell is the placement of a child within a grid layout: the row and
+// col of its first cell, and the number of rows and cols that it spans
+type GridCell struct {
+	Row, Col, RowSpan, ColSpan int
+}
+
+// Contains returns true if the cell covers given row and col
+func (gc *GridCell) Contains(row, col int) bool {
+	return row >= gc.Row && row < gc.Row+gc.RowSpan && col >= gc.Col && col < gc.Col+gc.ColSpan
+}
+
+// GridTrack specifies the size range of a track (column or row) of a grid
+// layout, as in CSS minmax(min, max)
+type GridTrack struct {
+	Min units.Value `desc:"minimum size of the track, which it keeps even if the layout is too small for it -- 0 = the size needed by its content"`
+	Max units.Value `desc:"maximum size of the track, which caps the preferred size of its content -- in fr units, the track stretches to fill the available space, with the fr value as its weight relative to other stretchy tracks -- 0 = the preferred size of its content"`
+}
+
+// ParseGridTracks parses the track sizes of a grid layout, as in CSS
+// grid-template-columns, separated by spaces: a size (e.g., 50px) for both
+// the min and max, auto for the size of the content, an fr weight (e.g.,
+// 1fr) to stretch to fill the space, or minmax(min, max) of those.  Returns
+// an error for unbalanced parentheses or a minmax without two sizes.
+func ParseGridTracks(str string) ([]GridTrack, error) {
+	var toks []string
+	depth, st := 0, -1
+	for i, r := range str + " " {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("gi.ParseGridTracks: unbalanced ) in %q", str)
+			}
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\n'):
+			if st >= 0 {
+				toks = append(toks, str[st:i])
+				st = -1
+			}
+			continue
+		}
+		if st < 0 {
+			st = i
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("gi.ParseGridTracks: unbalanced ( in %q", str)
+	}
+	tracks := make([]GridTrack, len(toks))
+	for i, tok := range toks {
+		tr := &tracks[i]
+		if args := strings.TrimPrefix(tok, "minmax("); args != tok {
+			mm := strings.Split(strings.TrimSuffix(args, ")"), ",")
+			if len(mm) != 2 {
+				return nil, fmt.Errorf("gi.ParseGridTracks: %q does not have a min and max size", tok)
+			}
+			tr.Min = gridTrackSize(mm[0])
+			tr.Max = gridTrackSize(mm[1])
+			continue
+		}
+		sz := gridTrackSize(tok)
+		if sz.Un != units.Fr { // an fr size only applies to the max
+			tr.Min = sz
+		}
+		tr.Max = sz
+	}
+	return tracks, nil
+}
+
+// gridTrackSize returns the size of a grid track from given string, for
+// ParseGridTracks -- auto is a 0 size, i.e., the size of the content
+func gridTrackSize(str string) units.Value {
+	str = strings.TrimSpace(str)
+	if str == "auto" {
+		return units.Value{}
+	}
+	return units.StringToValue(str)
+}
+
+// GridSizeKid records the size prefs and grid placement of a child of a grid
+// layout, as used in computing the track sizes -- see GridSizeCache
+type GridSizeKid struct {
+	Kid                        ki.Ki
+	Size                       gist.SizePrefs
+	Row, Col, RowSpan, ColSpan int
+	RowStart, ColStart, ColEnd int
+	Area                       string
+	AlignV                     gist.Align
+	BaseOff                    float32
+}
+
+// GridSizeCache caches the results of GatherSizesGrid, along with all of
+// the inputs that affect them, so the track sizes in GridData can be reused
+// when nothing affecting them has changed since the last pass.
+type GridSizeCache struct {
+	Valid      bool             `desc:"true if the results are valid for the inputs"`
+	Kids       []GridSizeKid    `desc:"children as used in the last pass"`
+	Columns    int              `desc:"columns style of the layout"`
+	AutoFit    int              `desc:"number of auto-fit columns of the layout"`
+	MinCols    int              `desc:"min columns of the layout"`
+	MaxCols    int              `desc:"max columns of the layout"`
+	AutoFitMin float32          `desc:"auto-fit-min-width of the layout, in dots"`
+	Areas      []string         `desc:"grid-template-areas of the layout"`
+	ColTracks  []GridTrack      `desc:"grid-template-columns of the layout"`
+	RowTracks  []GridTrack      `desc:"grid-template-rows of the layout"`
+	AlignItems GridItemAligns   `desc:"grid-align-items of the layout, which determines baseline alignment"`
+	RowAligns  []GridItemAligns `desc:"vertical alignments of the rows of the layout, which determine baseline alignment"`
+	Rows       int              `desc:"rows style of the layout"`
+	AutoRows   float32          `desc:"grid-auto-rows style of the layout, in dots"`
+	AutoCols   float32          `desc:"grid-auto-cols style of the layout, in dots"`
+	ScrollBar  float32          `desc:"scrollbar width of the layout, in dots"`
+	Spacing    float32          `desc:"spacing of the layout, in dots"`
+	BoxSpc     float32          `desc:"box space of the layout"`
+	PrefSizing bool             `desc:"whether sizing was for preferred size"`
+	SizeIn     gist.SizePrefs   `desc:"size prefs of the layout itself prior to the pass"`
+	AllocIn    mat32.Vec2       `desc:"alloc size of the layout prior to the pass"`
+	Size       gist.SizePrefs   `desc:"resulting size prefs of the layout"`
+	GridSize   image.Point      `desc:"resulting grid size"`
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // Layout
 
@@ -116,6 +345,10 @@ var LayoutFocusNameTimeoutMSec = 500
 // event to allow tab to focus on next element with same name.
 var LayoutFocusNameTabMSec = 2000
 
+// LayoutClipFunc is a function that modifies the clipping region for the
+// children of a Layout, as computed by ChildrenBBox2D -- see ClipModifier
+type LayoutClipFunc func(bb image.Rectangle) image.Rectangle
+
 // Layout is the primary node type responsible for organizing the sizes
 // and positions of child widgets.
 // All arbitrary collections of widgets should generally be contained
@@ -124,7 +357,8 @@ var LayoutFocusNameTabMSec = 2000
 // The alignment is NOT inherited by default so must be specified per
 // child, except that the parent alignment is used within the relevant
 // dimension (e.g., horizontal-align for a LayoutHoriz layout,
-// to determine left, right, center, justified).
+// to determine left, right, center, justified).  If InheritAlign is set,
+// children that do not set their own alignment use that of the layout.
 // Layouts can automatically add scrollbars depending on the Overflow
 // layout style.
 // For a Grid layout, the 'columns' property should generally be set
@@ -133,23 +367,66 @@ var LayoutFocusNameTabMSec = 2000
 // elements.
 type Layout struct {
 	WidgetBase
-	Lay           Layouts             `xml:"lay" desc:"type of layout to use"`
-	Spacing       units.Value         `xml:"spacing" desc:"extra space to add between elements in the layout"`
-	StackTop      int                 `desc:"for Stacked layout, index of node to use as the top of the stack -- only node at this index is rendered -- if not a valid index, nothing is rendered"`
-	StackTopOnly  bool                `desc:"for stacked layout, only layout the top widget -- this is appropriate for e.g., tab layout, which does a full redraw on stack changes, but not for e.g., check boxes which don't"`
-	ChildSize     mat32.Vec2          `copy:"-" json:"-" xml:"-" desc:"total max size of children as laid out"`
-	ExtraSize     mat32.Vec2          `copy:"-" json:"-" xml:"-" desc:"extra size in each dim due to scrollbars we add"`
-	HasScroll     [2]bool             `copy:"-" json:"-" xml:"-" desc:"whether scrollbar is used for given dim"`
-	Scrolls       [2]*ScrollBar       `copy:"-" json:"-" xml:"-" desc:"scroll bars -- we fully manage them as needed"`
-	GridSize      image.Point         `copy:"-" json:"-" xml:"-" desc:"computed size of a grid layout based on all the constraints -- computed during Size2D pass"`
-	GridData      [RowColN][]GridData `copy:"-" json:"-" xml:"-" desc:"grid data for rows in [0] and cols in [1]"`
-	FlowBreaks    []int               `copy:"-" json:"-" xml:"-" desc:"line breaks for flow layout"`
-	NeedsRedo     bool                `copy:"-" json:"-" xml:"-" desc:"true if this layout got a redo = true on previous iteration -- otherwise it just skips any re-layout on subsequent iteration"`
-	FocusName     string              `copy:"-" json:"-" xml:"-" desc:"accumulated name to search for when keys are typed"`
-	FocusNameTime time.Time           `copy:"-" json:"-" xml:"-" desc:"time of last focus name event -- for timeout"`
-	FocusNameLast ki.Ki               `copy:"-" json:"-" xml:"-" desc:"last element focused on -- used as a starting point if name is the same"`
-	ScrollsOff    bool                `copy:"-" json:"-" xml:"-" desc:"scrollbars have been manually turned off due to layout being invisible -- must be reactivated when re-visible"`
-	ScrollSig     ki.Signal           `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for layout scrolling -- sends signal whenever layout is scrolled due to user input -- signal type is dimension (mat32.X or Y) and data is new position (not delta)"`
+	Lay                Layouts                    `xml:"lay" desc:"type of layout to use"`
+	Spacing            units.Value                `xml:"spacing" desc:"extra space to add between elements in the layout"`
+	MinThumbSize       units.Value                `xml:"min-thumb-size" desc:"minimum size of the thumb of the scrollbars, so it remains usable for very large content -- if 0, SliderMinThumbSize is used"`
+	HScrollStep        units.Value                `xml:"h-scroll-step" desc:"amount to scroll per step (e.g., arrow key or wheel click) for the horizontal scrollbar -- page step is 10x this -- if 0, the width of a character in the current font is used"`
+	VScrollStep        units.Value                `xml:"v-scroll-step" desc:"amount to scroll per step (e.g., arrow key or wheel click) for the vertical scrollbar -- page step is 10x this -- if 0, the font size (i.e., one line) is used"`
+	AutoFitMin         units.Value                `xml:"auto-fit-min-width" desc:"for a grid layout, if > 0, the number of columns is computed from the allocated width as the number of columns of at least this width that fit (at least 1), with the columns stretched to fill the width -- like CSS repeat(auto-fit, minmax(w, 1fr)) -- overrides the columns property"`
+	MinColumns         int                        `xml:"min-columns" desc:"for a grid layout, the minimum number of columns computed automatically, from the AutoFitMin width or the number of elements -- 0 = no min"`
+	MaxColumns         int                        `xml:"max-columns" desc:"for a grid layout, the maximum number of columns computed automatically, from the AutoFitMin width or the number of elements -- 0 = no max"`
+	GridTemplateAreas  []string                   `xml:"grid-template-areas" desc:"for a grid layout, named areas of the grid, as in CSS grid-template-areas: one string per row, with the area name of each column separated by spaces, and . for an unnamed cell -- each name must form a rectangle -- children are placed into an area by their grid-area style property"`
+	StackTop           int                        `desc:"for Stacked layout, index of node to use as the top of the stack -- only node at this index is rendered -- if not a valid index, nothing is rendered"`
+	StackTopOnly       bool                       `desc:"for stacked layout, only layout the top widget -- this is appropriate for e.g., tab layout, which does a full redraw on stack changes, but not for e.g., check boxes which don't"`
+	BaselineSize       bool                       `desc:"for vertical layout, size the layout from the first baseline to the last baseline of its children, for those children that report a BaselineOffset (see Baseliner) -- for tight stacks of labels"`
+	FillStack          bool                       `desc:"for stacked layout, allocate the full content size of the layout to every child, positioned at the origin, so that switching the top of the stack does not resize the content"`
+	VScrollLeft        bool                       `desc:"dock the vertical scrollbar on the left side instead of the default right side, e.g., for right-to-left layouts"`
+	HScrollTop         bool                       `desc:"dock the horizontal scrollbar on the top instead of the default bottom"`
+	InheritAlign       bool                       `desc:"children that do not set their own horizontal-align or vertical-align properties use the alignment of this layout, instead of the default alignment -- e.g., to vertically center all the rows of a form"`
+	OverlayScroll      bool                       `desc:"render scrollbars on top of the content, without reserving any layout space for them, so content can scroll under them and does not reflow when they appear or disappear"`
+	CenterWhenFits     bool                       `desc:"vertically center the children as a group when their total height fits within the layout, e.g., for short content in a tall scrolling frame -- once the content overflows, it is top-aligned and scrolls as usual"`
+	StretchMode        StretchModes               `xml:"stretch-mode" desc:"how the extra space of the layout is distributed among its stretchy children (or the stretchy tracks of a grid): in proportion to their stretch weights (by default, their preferred sizes), or equally"`
+	KineticScroll      bool                       `desc:"flick scrolling with momentum: dragging on the layout scrolls its content, and releasing a fast drag continues scrolling with decelerating velocity until it stops or hits the end of the scrolling range -- for touch-friendly lists"`
+	KineticDecel       float32                    `desc:"rate of deceleration of KineticScroll, per second: the velocity decays exponentially as exp(-KineticDecel * t) -- if 0, LayoutKineticDecel is used"`
+	BreakWidth         float32                    `desc:"if > 0, a responsive breakpoint on the allocated width of the layout, in dots: below it, the layout type is BreakBelow, and above it, BreakAbove -- see SetBreakpoint"`
+	BreakBelow         Layouts                    `desc:"layout type to use below the BreakWidth"`
+	BreakAbove         Layouts                    `desc:"layout type to use above the BreakWidth"`
+	BreakSide          int                        `copy:"-" json:"-" xml:"-" view:"-" desc:"side of the BreakWidth last applied: -1 = below, 1 = above, 0 = none yet -- kept separately from Lay, which re-styling resets to the lay property, so the hysteresis band keeps the side"`
+	TextDir            gist.TextDirections        `desc:"direction of the layout along the horizontal: gist.LTR (the default) or gist.RTL -- for a horizontal layout with RTL, the children run from right to left, so start alignment and AlignJustify anchor from the right"`
+	ReverseRenderOrder bool                       `desc:"paint the children in the reverse of their tree order, so the first child is on top -- the z-index style property still takes precedence, and hit-testing follows the painting order"`
+	GridJustifyItems   GridItemAligns             `xml:"grid-justify-items" desc:"for a grid layout, the horizontal alignment of the children within their cells, for children that do not set their own horizontal-align -- unset leaves them at their default alignment -- like CSS justify-items"`
+	GridAlignItems     GridItemAligns             `xml:"grid-align-items" desc:"for a grid layout, the vertical alignment of the children within their cells, for children that do not set their own vertical-align -- unset leaves them at their default alignment -- like CSS align-items"`
+	GridRowAligns      []GridItemAligns           `desc:"for a grid layout, the vertical alignment of the children within the cells of each row, by row index, for children that do not set their own vertical-align -- overrides GridAlignItems for the rows where it is not unset, e.g., to bottom-align a header row -- see SetGridRowAlign"`
+	ChildSize          mat32.Vec2                 `copy:"-" json:"-" xml:"-" desc:"total max size of children as laid out"`
+	ExtraSize          mat32.Vec2                 `copy:"-" json:"-" xml:"-" desc:"extra size in each dim due to scrollbars we add"`
+	HasScroll          [2]bool                    `copy:"-" json:"-" xml:"-" desc:"whether scrollbar is used for given dim"`
+	Scrolls            [2]*ScrollBar              `copy:"-" json:"-" xml:"-" desc:"scroll bars -- we fully manage them as needed"`
+	GridSize           image.Point                `copy:"-" json:"-" xml:"-" desc:"computed size of a grid layout based on all the constraints -- computed during Size2D pass"`
+	GridData           [RowColN][]GridData        `copy:"-" json:"-" xml:"-" desc:"grid data for rows in [0] and cols in [1]"`
+	AutoFitCols        int                        `copy:"-" json:"-" xml:"-" desc:"number of columns computed from the allocated width for AutoFitMin, as of the last layout -- 0 if not yet computed"`
+	GridTemplateCols   []GridTrack                `xml:"grid-template-columns" desc:"for a grid layout, the size ranges of the columns, in order, as in CSS grid-template-columns, e.g., minmax(100px, 1fr) 50px auto -- columns beyond these are sized to their content -- see ParseGridTracks"`
+	GridTemplateRows   []GridTrack                `xml:"grid-template-rows" desc:"for a grid layout, the size ranges of the rows, in order, as for GridTemplateCols"`
+	GridAreas          map[string]image.Rectangle `copy:"-" json:"-" xml:"-" desc:"cells covered by each named area of GridTemplateAreas, with X = col and Y = row -- parsed from GridTemplateAreas as needed, and reset to nil when it changes"`
+	GridCache          GridSizeCache              `copy:"-" json:"-" xml:"-" view:"-" desc:"cached results of the grid size pass, along with everything that affects them, so they can be reused when nothing has changed, e.g., when re-laying out after scrolling"`
+	FlowBreaks         []int                      `copy:"-" json:"-" xml:"-" desc:"line breaks for flow layout"`
+	NeedsRedo          bool                       `copy:"-" json:"-" xml:"-" desc:"true if this layout got a redo = true on previous iteration -- otherwise it just skips any re-layout on subsequent iteration"`
+	FocusName          string                     `copy:"-" json:"-" xml:"-" desc:"accumulated name to search for when keys are typed"`
+	FocusNameTime      time.Time                  `copy:"-" json:"-" xml:"-" desc:"time of last focus name event -- for timeout"`
+	FocusNameLast      ki.Ki                      `copy:"-" json:"-" xml:"-" desc:"last element focused on -- used as a starting point if name is the same"`
+	ScrollsOff         bool                       `copy:"-" json:"-" xml:"-" desc:"scrollbars have been manually turned off due to layout being invisible -- must be reactivated when re-visible"`
+	ScrollSig          ki.Signal                  `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for layout scrolling -- sends signal whenever layout is scrolled due to user input -- signal type is dimension (mat32.X or Y) and data is new position (not delta)"`
+	ScrollBarsOn       [2]bool                    `copy:"-" json:"-" xml:"-" desc:"whether scrollbar was present for given dim as of the last completed layout -- use ScrollBarsActive to access"`
+	ScrollBarsSig      ki.Signal                  `copy:"-" json:"-" xml:"-" view:"-" desc:"signal sent whenever the presence of a scrollbar changes across layouts -- signal type is dimension (mat32.X or Y) and data is bool of whether the scrollbar is now present"`
+	ClipModifier       LayoutClipFunc             `copy:"-" json:"-" xml:"-" view:"-" desc:"optional function applied at the end of ChildrenBBox2D to further modify the clipping region for the children, e.g., to carve out a pinned header region"`
+	NearEndThr         float32                    `copy:"-" json:"-" xml:"-" view:"-" desc:"threshold distance from the end of the scrolling range, within which NearEndFunc is called -- see OnScrollNearEnd"`
+	NearEndFunc        func()                     `copy:"-" json:"-" xml:"-" view:"-" desc:"function called when scrolled to within NearEndThr of the end of the scrolling range, e.g., to add more children -- see OnScrollNearEnd"`
+	ResizeFunc         func(old, nw mat32.Vec2)   `copy:"-" json:"-" xml:"-" view:"-" desc:"function called when the allocated size of the layout changes by at least LayoutResizeTol, with the old and new sizes -- see OnResize"`
+	ResizeSize         mat32.Vec2                 `copy:"-" json:"-" xml:"-" view:"-" desc:"allocated size as of the last call of ResizeFunc"`
+	KineticVel         mat32.Vec2                 `copy:"-" json:"-" xml:"-" view:"-" desc:"current velocity of kinetic scrolling, in dots per second -- tracked while dragging, and decelerated after release"`
+	KineticTime        time.Time                  `copy:"-" json:"-" xml:"-" view:"-" desc:"time of the last drag event used for tracking KineticVel, and then of the last frame of kinetic scrolling after release"`
+	KineticTicker      *time.Ticker               `copy:"-" json:"-" xml:"-" view:"-" desc:"ticker timing the frames of kinetic scrolling after release -- nil if not running"`
+	KineticPending     bool                       `copy:"-" json:"-" xml:"-" view:"-" desc:"true if a frame of kinetic scrolling has been sent to the window and not yet processed"`
+	NearEndOn          [2]bool                    `copy:"-" json:"-" xml:"-" view:"-" desc:"whether scrolling is currently within NearEndThr of the end, in each dimension -- NearEndFunc is only called again after scrolling away from the end"`
 }
 
 var KiT_Layout = kit.Types.AddType(&Layout{}, LayoutProps)
@@ -175,7 +452,34 @@ func (ly *Layout) CopyFieldsFrom(frm interface{}) {
 	ly.WidgetBase.CopyFieldsFrom(&fr.WidgetBase)
 	ly.Lay = fr.Lay
 	ly.Spacing = fr.Spacing
+	ly.MinThumbSize = fr.MinThumbSize
+	ly.HScrollStep = fr.HScrollStep
+	ly.VScrollStep = fr.VScrollStep
+	ly.AutoFitMin = fr.AutoFitMin
+	ly.MinColumns = fr.MinColumns
+	ly.MaxColumns = fr.MaxColumns
+	ly.GridTemplateAreas = append([]string(nil), fr.GridTemplateAreas...)
+	ly.GridTemplateCols = append([]GridTrack(nil), fr.GridTemplateCols...)
+	ly.GridTemplateRows = append([]GridTrack(nil), fr.GridTemplateRows...)
+	ly.GridAreas = nil
 	ly.StackTop = fr.StackTop
+	ly.FillStack = fr.FillStack
+	ly.BaselineSize = fr.BaselineSize
+	ly.VScrollLeft = fr.VScrollLeft
+	ly.HScrollTop = fr.HScrollTop
+	ly.InheritAlign = fr.InheritAlign
+	ly.CenterWhenFits = fr.CenterWhenFits
+	ly.StretchMode = fr.StretchMode
+	ly.KineticScroll = fr.KineticScroll
+	ly.KineticDecel = fr.KineticDecel
+	ly.BreakWidth = fr.BreakWidth
+	ly.BreakBelow = fr.BreakBelow
+	ly.BreakAbove = fr.BreakAbove
+	ly.TextDir = fr.TextDir
+	ly.ReverseRenderOrder = fr.ReverseRenderOrder
+	ly.GridJustifyItems = fr.GridJustifyItems
+	ly.GridAlignItems = fr.GridAlignItems
+	ly.GridRowAligns = append([]GridItemAligns(nil), fr.GridRowAligns...)
 }
 
 // Layouts are the different types of layouts
@@ -213,6 +517,13 @@ const (
 	// parent wants to take over the job of the layout
 	LayoutNil
 
+	// LayoutBorder arranges items in the regions given by their region
+	// property, as in a Java BorderLayout: north and south span the top and
+	// bottom edges, west and east the left and right edges between them,
+	// each at its preferred size, and center gets the rest of the space --
+	// e.g., a header and footer around a scrolling body
+	LayoutBorder
+
 	LayoutsN
 )
 
@@ -239,6 +550,169 @@ func (ev *RowCol) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(e
 
 //go:generate stringer -type=RowCol
 
+// StretchModes determine how the extra space of a layout is distributed
+// among its stretchy children, or the stretchy tracks of a grid
+type StretchModes int32
+
+const (
+	// StretchProportional gives each stretchy element extra space in
+	// proportion to its stretch weight -- by default, its preferred size
+	StretchProportional StretchModes = iota
+
+	// StretchEqual gives each stretchy element the same extra space,
+	// regardless of its preferred size
+	StretchEqual
+
+	StretchModesN
+)
+
+//go:generate stringer -type=StretchModes
+
+var KiT_StretchModes = kit.Enums.AddEnumAltLower(StretchModesN, kit.NotBitFlag, nil, "Stretch")
+
+func (ev StretchModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *StretchModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// GridItemAligns are the alignments of the children of a grid layout within
+// their cells, for GridJustifyItems and GridAlignItems
+type GridItemAligns int32
+
+const (
+	// GridItemsUnset means the alignment is not specified, so the children
+	// use their own alignment -- distinct from explicitly GridItemsStart
+	GridItemsUnset GridItemAligns = iota
+
+	// GridItemsStart aligns the children to the start (left or top) of their cells
+	GridItemsStart
+
+	// GridItemsCenter centers the children within their cells
+	GridItemsCenter
+
+	// GridItemsEnd aligns the children to the end (right or bottom) of their cells
+	GridItemsEnd
+
+	// GridItemsStretch stretches the children to fill their cells
+	GridItemsStretch
+
+	GridItemAlignsN
+)
+
+//go:generate stringer -type=GridItemAligns
+
+var KiT_GridItemAligns = kit.Enums.AddEnumAltLower(GridItemAlignsN, kit.NotBitFlag, nil, "GridItems")
+
+func (ev GridItemAligns) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *GridItemAligns) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// Align returns the corresponding alignment along given dimension, for
+// a set alignment -- GridItemsUnset returns AlignN
+func (ev GridItemAligns) Align(dim mat32.Dims) gist.Align {
+	switch ev {
+	case GridItemsStart:
+		if dim == mat32.X {
+			return gist.AlignLeft
+		}
+		return gist.AlignTop
+	case GridItemsCenter:
+		if dim == mat32.X {
+			return gist.AlignCenter
+		}
+		return gist.AlignMiddle
+	case GridItemsEnd:
+		if dim == mat32.X {
+			return gist.AlignRight
+		}
+		return gist.AlignBottom
+	case GridItemsStretch:
+		return gist.AlignJustify // treated as stretch in a cell
+	}
+	return gist.AlignN
+}
+
+// LayoutRoundings are the policies for converting sizes and positions in
+// dots, computed by the layout, into integer pixel bounding boxes
+type LayoutRoundings int32
+
+const (
+	// RoundPosSize floors the position and takes the ceiling of the size,
+	// so a bounding box always covers its full allocation, but may overlap
+	// an adjacent one by a pixel
+	RoundPosSize LayoutRoundings = iota
+
+	// RoundNearest rounds each edge to the nearest pixel, so adjacent
+	// bounding boxes tile without gaps or overlap
+	RoundNearest
+
+	// RoundFloor floors each edge, so adjacent bounding boxes tile without
+	// gaps or overlap
+	RoundFloor
+
+	// RoundCeil takes the ceiling of each edge, so adjacent bounding boxes
+	// tile without gaps or overlap
+	RoundCeil
+
+	LayoutRoundingsN
+)
+
+//go:generate stringer -type=LayoutRoundings
+
+var KiT_LayoutRoundings = kit.Enums.AddEnumAltLower(LayoutRoundingsN, kit.NotBitFlag, nil, "Round")
+
+func (ev LayoutRoundings) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *LayoutRoundings) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// LayoutRounding is the policy used for converting layout dots into integer
+// pixels in all bounding box computations -- one of the edge-based policies
+// avoids seams between adjacent widgets at fractional DPI scales
+var LayoutRounding = RoundPosSize
+
+// LayoutRoundDots converts given value in dots into integer pixels according
+// to LayoutRounding -- RoundPosSize floors, as for positions
+func LayoutRoundDots(dots float32) int {
+	switch LayoutRounding {
+	case RoundNearest:
+		return int(mat32.Round(dots))
+	case RoundCeil:
+		return int(mat32.Ceil(dots))
+	default:
+		return int(mat32.Floor(dots))
+	}
+}
+
+// LayoutRoundPoint converts given point in dots into integer pixels
+// according to LayoutRounding
+func LayoutRoundPoint(dots mat32.Vec2) image.Point {
+	return image.Pt(LayoutRoundDots(dots.X), LayoutRoundDots(dots.Y))
+}
+
+// LayoutRect returns the integer pixel bounding box for given position and
+// size in dots, according to LayoutRounding -- the edge-based policies round
+// the position and the far edge (position + size) in the same way.
+func LayoutRect(pos, sz mat32.Vec2) image.Rectangle {
+	if LayoutRounding == RoundPosSize {
+		return mat32.RectFromPosSizeMax(pos, sz)
+	}
+	return image.Rectangle{Min: LayoutRoundPoint(pos), Max: LayoutRoundPoint(pos.Add(sz))}
+}
+
+// Baseliner is implemented by nodes that contain text and can report the
+// offset of their first text baseline from their top edge, used for
+// BaselineSize in layouts.
+type Baseliner interface {
+	// BaselineOffset returns the offset of the first baseline from the top
+	BaselineOffset() float32
+}
+
+// DebugLayoutBounds draws outlines of the allocated box of each child of
+// every layout, and of its margin, and of the children (clip) box of the
+// layout, on top of the normal rendering -- for diagnosing layout issues.
+var DebugLayoutBounds = false
+
+// DebugLayoutColors are the translucent colors used for DebugLayoutBounds,
+// for the allocated boxes of the children, their margins, and the children
+// box of the layout, respectively.
+var DebugLayoutColors = [3]color.RGBA{{255, 0, 0, 160}, {0, 128, 255, 160}, {0, 192, 0, 160}}
+
 // LayoutDefault is default obj that can be used when property specifies "default"
 var LayoutDefault Layout
 
@@ -287,7 +761,9 @@ func (ly *Layout) ManageOverflow() {
 				// 	fmt.Printf("overflow, setting scb: %v\n", d)
 				// }
 				ly.HasScroll[d] = true
-				ly.ExtraSize.SetAddDim(odim, sbw)
+				if ly.ShowScrollBars() && !ly.OverlayScroll {
+					ly.ExtraSize.SetAddDim(odim, sbw)
+				}
 			}
 		}
 		for d := mat32.X; d <= mat32.Y; d++ {
@@ -299,27 +775,114 @@ func (ly *Layout) ManageOverflow() {
 	}
 }
 
+// PredictScrolls returns the space that scrollbars will take in each dim,
+// for a LayoutHoriz or LayoutVert whose children already need more than the
+// available size: the width of a vertical scrollbar in X if they overflow
+// in Y, and vice-versa.  Layout2D reserves this space while laying out the
+// children, so that children stretching along the other dim fit beside the
+// scrollbar, instead of overshooting under it, as ManageOverflow only adds
+// the scrollbars after the layout.
+func (ly *Layout) PredictScrolls() mat32.Vec2 {
+	var sbs mat32.Vec2
+	if (ly.Lay != LayoutHoriz && ly.Lay != LayoutVert) || ly.Sty.Layout.Overflow == gist.OverflowHidden || !ly.ShowScrollBars() || ly.OverlayScroll {
+		return sbs
+	}
+	var sum, max mat32.Vec2
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		sum.SetAdd(ni.LayState.Size.Need)
+		max.SetMax(ni.LayState.Size.Need)
+	}
+	sd := LaySummedDim(ly.Lay)
+	need := max
+	need.SetDim(sd, sum.Dim(sd)+float32(ints.MaxInt(NumShownKids(ly)-1, 0))*ly.Spacing.Dots)
+	need.SetAddScalar(ly.BoxSpace())
+	avail := ly.AvailSize()
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if need.Dim(d) > (avail.Dim(d) + 2.0) { // same margin as ManageOverflow
+			sbs.SetDim(mat32.OtherDim(d), ly.Sty.Layout.ScrollBarWidth.Dots)
+		}
+	}
+	return sbs
+}
+
+// UpdateScrollBarsOn records the current presence of scrollbars in
+// ScrollBarsOn, emitting a ScrollBarsSig signal for each dimension where
+// it has changed.  Called once at the end of Layout2D, when the layout is
+// complete, so that transient states of the layout passes are not signaled.
+func (ly *Layout) UpdateScrollBarsOn() {
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if ly.ScrollBarsOn[d] == ly.HasScroll[d] {
+			continue
+		}
+		ly.ScrollBarsOn[d] = ly.HasScroll[d]
+		ly.ScrollBarsSig.Emit(ly.This(), int64(d), ly.HasScroll[d])
+	}
+}
+
+// ScrollBarsActive returns whether the horizontal and vertical scrollbars
+// are present, as of the last completed layout -- this is stable across
+// the layout passes, in contrast to HasScroll which is updated during them.
+func (ly *Layout) ScrollBarsActive() (h, v bool) {
+	return ly.ScrollBarsOn[mat32.X], ly.ScrollBarsOn[mat32.Y]
+}
+
 // HasAnyScroll returns true if layout has
 func (ly *Layout) HasAnyScroll() bool {
 	return ly.HasScroll[mat32.X] || ly.HasScroll[mat32.Y]
 }
 
-// SetScroll sets a scrollbar along given dimension
+// EnsureScroll returns the scrollbar along given dimension, creating it on
+// first use -- once created, it persists for the life of the layout, and
+// is only deactivated when not needed (see DeactivateScroll), so the same
+// scrollbar is reused across changes in overflow.  Layouts that never
+// overflow never create a scrollbar.
+func (ly *Layout) EnsureScroll(d mat32.Dims) *ScrollBar {
+	if sc := ly.Scrolls[d]; sc != nil {
+		return sc
+	}
+	sc := &ScrollBar{}
+	sc.InitName(sc, fmt.Sprintf("Scroll%v", d))
+	ki.SetParent(sc, ly.This())
+	sc.Dim = d
+	sc.Init2D()
+	sc.Defaults()
+	sc.Tracking = true
+	sc.Min = 0.0
+	sc.SliderSig.ConnectOnly(ly.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(SliderValueChanged) {
+			return
+		}
+		li, _ := KiToNode2D(recv)
+		ls := li.AsLayout2D()
+		wupdt := ls.TopUpdateStart()
+		ls.Move2DTree()
+		li.UpdateSig()
+		ls.TopUpdateEnd(wupdt)
+		ls.CheckScrollNearEnd(send.(*ScrollBar).Dim)
+	})
+	ly.Scrolls[d] = sc
+	return sc
+}
+
+// SetScroll sets a scrollbar along given dimension, creating it if needed
+// (see EnsureScroll), and updating its size and range for the current
+// layout.  The scroll value is clamped to the new range, and kept at the
+// end if it was at the end before, e.g., when content shrinks or grows
+// while scrolled to the bottom.
 func (ly *Layout) SetScroll(d mat32.Dims) {
-	if ly.Scrolls[d] == nil {
-		ly.Scrolls[d] = &ScrollBar{}
-		sc := ly.Scrolls[d]
-		sc.InitName(sc, fmt.Sprintf("Scroll%v", d))
-		ki.SetParent(sc, ly.This())
-		sc.Dim = d
-		sc.Init2D()
-		sc.Defaults()
-		sc.Tracking = true
-		sc.Min = 0.0
-	}
+	sc := ly.EnsureScroll(d)
+	// scrolled to the end of the range as of the last layout: stays at the end
+	// of the new range, except where NearEndFunc is adding content at the end
+	pin := ly.ScrollBarsOn[d] && ly.NearEndFunc == nil && sc.Value > 0 && sc.Value >= sc.Max-sc.ThumbVal
 	spc := ly.BoxSpace()
 	avail := ly.AvailSize().SubScalar(spc * 2.0)
-	sc := ly.Scrolls[d]
 	if d == mat32.X {
 		sc.SetFixedHeight(ly.Sty.Layout.ScrollBarWidth)
 		sc.SetFixedWidth(units.NewValue(avail.Dim(d), units.Dot))
@@ -329,23 +892,98 @@ func (ly *Layout) SetScroll(d mat32.Dims) {
 	}
 	sc.Style2D()
 	sc.Max = ly.ChildSize.Dim(d) + ly.ExtraSize.Dim(d) // only scrollbar
-	sc.Step = ly.Sty.Font.Size.Dots                    // step by lines
-	sc.PageStep = 10.0 * sc.Step                       // todo: more dynamic
+	sc.Step = ly.ScrollStep(d)
+	sc.PageStep = 10.0 * sc.Step // todo: more dynamic
 	sc.ThumbVal = avail.Dim(d) - spc
+	sc.MinThSize = ly.MinThumbSize.Dots
 	sc.TrackThr = sc.Step
-	sc.Value = mat32.Min(sc.Value, sc.Max-sc.ThumbVal) // keep in range
+	end := mat32.Max(sc.Max-sc.ThumbVal, 0)
+	if pin {
+		sc.Value = end
+	} else {
+		sc.Value = mat32.Min(sc.Value, end) // keep in range
+	}
 	// fmt.Printf("set sc lay: %v  max: %v  val: %v\n", ly.Path(), sc.Max, sc.Value)
-	sc.SliderSig.ConnectOnly(ly.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
-		if sig != int64(SliderValueChanged) {
-			return
+}
+
+// ScrollStep returns the amount to scroll per step for the scrollbar
+// in given dimension: HScrollStep or VScrollStep if set, and otherwise
+// the width of a character for horizontal and the font size (one line)
+// for vertical scrolling.
+func (ly *Layout) ScrollStep(d mat32.Dims) float32 {
+	if d == mat32.X {
+		if ly.HScrollStep.Dots > 0 {
+			return ly.HScrollStep.Dots
 		}
-		li, _ := KiToNode2D(recv)
-		ls := li.AsLayout2D()
-		wupdt := ls.TopUpdateStart()
-		ls.Move2DTree()
-		li.UpdateSig()
-		ls.TopUpdateEnd(wupdt)
-	})
+		if ly.Sty.Font.Face != nil && ly.Sty.Font.Face.Metrics.Ch > 0 {
+			return ly.Sty.Font.Face.Metrics.Ch
+		}
+		return ly.Sty.Font.Size.Dots
+	}
+	if ly.VScrollStep.Dots > 0 {
+		return ly.VScrollStep.Dots
+	}
+	return ly.Sty.Font.Size.Dots // step by lines
+}
+
+// OnScrollNearEnd sets a function to call when the layout is scrolled to
+// within given threshold distance (in dots) of the end of its scrolling
+// range, e.g., to lazily add more children for an infinite scroll view.
+// The function is called once each time the end is approached: it is not
+// called again until scrolling has moved away from the end.
+func (ly *Layout) OnScrollNearEnd(threshold float32, fn func()) {
+	ly.NearEndThr = threshold
+	ly.NearEndFunc = fn
+	ly.NearEndOn = [2]bool{}
+}
+
+// LayoutResizeTol is the minimum change in the allocated size of a layout,
+// in dots along either dimension, that calls its OnResize function -- so
+// that sub-pixel jitter between layout passes is ignored
+var LayoutResizeTol = float32(1)
+
+// OnResize sets a function to call when the allocated size of the layout
+// changes, with the old and new sizes, e.g., to switch between compact and
+// full versions of the content at a breakpoint.  It is called during the
+// Layout2D pass, including for the first size, with an old size of 0 -- any
+// structural changes it makes are rendered in a subsequent pass.  Changes
+// of less than LayoutResizeTol are ignored.
+func (ly *Layout) OnResize(fn func(old, nw mat32.Vec2)) {
+	ly.ResizeFunc = fn
+	ly.ResizeSize = mat32.Vec2Zero
+}
+
+// CheckResize calls the OnResize function if the allocated size has
+// changed by at least LayoutResizeTol since it was last called.
+func (ly *Layout) CheckResize() {
+	if ly.ResizeFunc == nil {
+		return
+	}
+	old := ly.ResizeSize
+	nw := ly.LayState.Alloc.Size
+	if mat32.Abs(nw.X-old.X) < LayoutResizeTol && mat32.Abs(nw.Y-old.Y) < LayoutResizeTol {
+		return
+	}
+	ly.ResizeSize = nw
+	ly.ResizeFunc(old, nw)
+}
+
+// CheckScrollNearEnd checks if the scrollbar in given dimension is within
+// NearEndThr of the end of its range, calling NearEndFunc when it first
+// gets there -- called when the scroll value changes.
+func (ly *Layout) CheckScrollNearEnd(d mat32.Dims) {
+	if ly.NearEndFunc == nil || !ly.HasScroll[d] {
+		return
+	}
+	sc := ly.Scrolls[d]
+	near := sc.Value >= sc.Max-sc.ThumbVal-ly.NearEndThr
+	if near == ly.NearEndOn[d] {
+		return
+	}
+	ly.NearEndOn[d] = near
+	if near {
+		ly.NearEndFunc()
+	}
 }
 
 // DeleteScroll deletes scrollbar along given dimesion.  todo: we are leaking
@@ -378,27 +1016,57 @@ func (ly *Layout) LayoutScrolls() {
 	avail := ly.AvailSize()
 	for d := mat32.X; d <= mat32.Y; d++ {
 		odim := mat32.OtherDim(d)
-		if ly.HasScroll[d] {
+		if ly.HasScroll[d] && ly.ShowScrollBars() {
 			sc := ly.Scrolls[d]
 			sc.Size2D(0)
 			sc.LayState.Alloc.PosRel.SetDim(d, spc)
-			sc.LayState.Alloc.PosRel.SetDim(odim, avail.Dim(odim)-sbw-2.0)
+			if ly.ScrollAtStart(d) {
+				sc.LayState.Alloc.PosRel.SetDim(odim, spc)
+			} else {
+				sc.LayState.Alloc.PosRel.SetDim(odim, avail.Dim(odim)-sbw-2.0)
+			}
 			sc.LayState.Alloc.Size.SetDim(d, avail.Dim(d)-spc)
 			if ly.HasScroll[odim] { // make room for other
 				sc.LayState.Alloc.Size.SetSubDim(d, sbw)
+				if ly.ScrollAtStart(odim) {
+					sc.LayState.Alloc.PosRel.SetAddDim(d, sbw)
+				}
 			}
 			sc.LayState.Alloc.Size.SetDim(odim, sbw)
 			sc.Layout2D(ly.VpBBox, 0) // this will add parent position to above rel pos
 		} else {
 			if ly.Scrolls[d] != nil {
 				ly.DeactivateScroll(ly.Scrolls[d])
+				if ly.HasScroll[d] { // hidden, only used for the scroll range
+					ly.Scrolls[d].DisconnectAllEvents(AllPris)
+				}
 			}
 		}
 	}
 }
 
-// RenderScrolls draws the scrollbars
+// ScrollAtStart returns true if the scrollbar for given dimension is docked
+// at the start (left or top) of the other dimension, instead of the default
+// end (right or bottom)
+func (ly *Layout) ScrollAtStart(d mat32.Dims) bool {
+	if d == mat32.Y {
+		return ly.VScrollLeft
+	}
+	return ly.HScrollTop
+}
+
+// ShowScrollBars returns true if the scrollbars are shown when present --
+// false for OverflowFade, which only uses them for the scrolling logic, so
+// they are not laid out, rendered, or connected to events
+func (ly *Layout) ShowScrollBars() bool {
+	return ly.Sty.Layout.Overflow != gist.OverflowFade
+}
+
+// RenderScrolls draws the scrollbars, if shown
 func (ly *Layout) RenderScrolls() {
+	if !ly.ShowScrollBars() {
+		return
+	}
 	for d := mat32.X; d <= mat32.Y; d++ {
 		if ly.HasScroll[d] {
 			ly.Scrolls[d].Render2D()
@@ -406,6 +1074,126 @@ func (ly *Layout) RenderScrolls() {
 	}
 }
 
+// RenderContent renders the children along with the scrollbars, which go
+// below the children, or on top of them for OverlayScroll, and then the
+// fades of OverflowFade -- for the Render2D of Layout and of the types that
+// embed it, e.g., Frame.
+func (ly *Layout) RenderContent() {
+	if !ly.OverlayScroll {
+		ly.RenderScrolls()
+	}
+	ly.Render2DChildren()
+	if ly.OverlayScroll { // on top of the content
+		ly.RenderScrolls()
+	}
+	ly.RenderFades()
+}
+
+// OverflowFadeSize is the size in dots of the fade drawn at the edges of
+// layouts with OverflowFade
+var OverflowFadeSize = 24
+
+// RenderFades draws a fade to the background color at the edges of the
+// layout where there is more content to scroll to, for OverflowFade
+func (ly *Layout) RenderFades() {
+	if ly.Sty.Layout.Overflow != gist.OverflowFade || !ly.HasAnyScroll() {
+		return
+	}
+	cb := ly.ChildrenBBox2D() // before locking the style
+	rs, _, st := ly.RenderLock()
+	defer ly.RenderUnlock(rs)
+	bg := st.Font.BgColor.Color
+	if bg.IsNil() {
+		bg = Prefs.Colors.Background
+	}
+	cb = cb.Intersect(rs.Bounds)
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if !ly.HasScroll[d] {
+			continue
+		}
+		sc := ly.Scrolls[d]
+		if sc.Value > 0 {
+			renderFade(rs, cb, d, false, bg)
+		}
+		if sc.Value < sc.Max-sc.ThumbVal {
+			renderFade(rs, cb, d, true, bg)
+		}
+	}
+}
+
+// RenderDebugBounds draws the outlines of the allocated boxes of the
+// children, and of their margins, and of the children box of the layout,
+// in the DebugLayoutColors -- see DebugLayoutBounds.
+func (ly *Layout) RenderDebugBounds() {
+	cb := ly.ChildrenBBox2D() // before locking the style
+	rs, pc, _ := ly.RenderLock()
+	defer ly.RenderUnlock(rs)
+	pc.FillStyle.SetColor(nil)
+	pc.StrokeStyle.Width.Dots = 1
+	for _, k := range ly.PaintKids() {
+		nii, ni := KiToNode2D(k)
+		if nii == nil || ni.IsInvisible() {
+			continue
+		}
+		wb := nii.AsWidget()
+		if wb == nil {
+			continue
+		}
+		pos, sz := wb.LayState.Alloc.Pos, wb.LayState.Alloc.Size
+		if sz.X <= 0 || sz.Y <= 0 {
+			continue
+		}
+		renderDebugBox(rs, pc, pos, sz, DebugLayoutColors[0])
+		wb.StyMu.RLock()
+		marg := wb.Sty.Layout.Margin.Dots
+		wb.StyMu.RUnlock()
+		if marg != 0 {
+			renderDebugBox(rs, pc, pos.AddScalar(marg), sz.SubScalar(2*marg), DebugLayoutColors[1])
+		}
+	}
+	renderDebugBox(rs, pc, mat32.NewVec2FmPoint(cb.Min), mat32.NewVec2FmPoint(cb.Size()), DebugLayoutColors[2])
+}
+
+// renderDebugBox strokes a one pixel outline just inside of given box
+func renderDebugBox(rs *girl.State, pc *girl.Paint, pos, sz mat32.Vec2, clr color.RGBA) {
+	if sz.X < 1 || sz.Y < 1 {
+		return
+	}
+	pc.StrokeStyle.SetColor(clr)
+	pc.DrawRectangle(rs, pos.X+0.5, pos.Y+0.5, sz.X-1, sz.Y-1)
+	pc.Stroke(rs)
+}
+
+// renderFade draws a fade to given color at the start or end of given
+// box along given dimension
+func renderFade(rs *girl.State, bb image.Rectangle, d mat32.Dims, end bool, clr gist.Color) {
+	sz := bb.Size()
+	n := ints.MinInt(OverflowFadeSize, sz.X)
+	if d == mat32.Y {
+		n = ints.MinInt(OverflowFadeSize, sz.Y)
+	}
+	for i := 0; i < n; i++ { // i = distance from edge
+		a := 1 - float32(i)/float32(n)
+		ln := bb
+		switch {
+		case d == mat32.X && end:
+			ln.Min.X = bb.Max.X - i - 1
+			ln.Max.X = ln.Min.X + 1
+		case d == mat32.X:
+			ln.Min.X = bb.Min.X + i
+			ln.Max.X = ln.Min.X + 1
+		case end:
+			ln.Min.Y = bb.Max.Y - i - 1
+			ln.Max.Y = ln.Min.Y + 1
+		default:
+			ln.Min.Y = bb.Min.Y + i
+			ln.Max.Y = ln.Min.Y + 1
+		}
+		fc := color.RGBA{uint8(a * float32(clr.R)), uint8(a * float32(clr.G)), uint8(a * float32(clr.B)), uint8(a * float32(clr.A))}
+		draw.Draw(rs.Image, ln, &image.Uniform{fc}, image.ZP, draw.Over)
+	}
+}
+
 // ReRenderScrolls re-draws the scrollbars de-novo -- can be called ad-hoc by others
 func (ly *Layout) ReRenderScrolls() {
 	if ly.PushBounds() {
@@ -432,6 +1220,9 @@ func (ly *Layout) SetScrollsOff() {
 // layouts -- critical to call this BEFORE we add our own delta, which is
 // generated from these very same scrollbars.
 func (ly *Layout) Move2DScrolls(delta image.Point, parBBox image.Rectangle) {
+	if !ly.ShowScrollBars() {
+		return
+	}
 	for d := mat32.X; d <= mat32.Y; d++ {
 		if ly.HasScroll[d] {
 			ly.Scrolls[d].Move2D(delta, parBBox)
@@ -458,50 +1249,564 @@ func (ly *Layout) ScrollActionPos(dim mat32.Dims, pos float32) {
 	}
 }
 
-// ScrollToPos moves the scrollbar in given dimension to given
-// position and DOES NOT emit a ScrollSig signal.
-func (ly *Layout) ScrollToPos(dim mat32.Dims, pos float32) {
-	if ly.HasScroll[dim] {
-		ly.Scrolls[dim].SetValueAction(pos)
-	}
+// ScrollToPos moves the scrollbar in given dimension to given
+// position and DOES NOT emit a ScrollSig signal.
+func (ly *Layout) ScrollToPos(dim mat32.Dims, pos float32) {
+	if ly.HasScroll[dim] {
+		ly.Scrolls[dim].SetValueAction(pos)
+	}
+}
+
+// ScrollByPixels scrolls by given delta in pixels (dots) in each dimension
+// that has a scrollbar, applied directly to the scrollbar values (clamped
+// to their range), without any stepping -- for the small fractional deltas
+// of Precise scroll events from trackpads.  Emits a ScrollSig signal for
+// each dimension scrolled.
+func (ly *Layout) ScrollByPixels(delta mat32.Vec2) {
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if dd := delta.Dim(d); dd != 0 {
+			ly.ScrollActionDelta(d, dd)
+		}
+	}
+}
+
+// ScrollDelta processes a scroll event.  If only one dimension is processed,
+// and there is a non-zero in other, then the consumed dimension is reset to 0
+// and the event is left unprocessed, so a higher level can consume the
+// remainder.  A vertical wheel delta with the Shift (or Alt) modifier
+// scrolls horizontally instead, if there is a horizontal scrollbar.
+// Precise events are applied in exact pixels using ScrollByPixels.
+func (ly *Layout) ScrollDelta(me *mouse.ScrollEvent) {
+	del := me.Delta
+	if del.X == 0 && me.HasAnyModifier(key.Shift, key.Alt) {
+		// shift or alt says: use vert wheel for horizontal -- if we have no
+		// horizontal scrolling, leave it for a parent that does
+		if ly.HasScroll[mat32.X] {
+			dy := float32(del.Y)
+			if me.Precise {
+				dy = me.PreciseDelta.Y
+			}
+			ly.ScrollActionDelta(mat32.X, dy)
+			me.SetProcessed()
+		}
+		return
+	}
+	if me.Precise {
+		pd := me.PreciseDelta
+		for d := mat32.X; d <= mat32.Y; d++ {
+			if !ly.HasScroll[d] {
+				pd.SetDim(d, 0)
+			}
+		}
+		ly.ScrollByPixels(pd)
+		me.PreciseDelta.SetSub(pd) // remainder for a higher level
+		if me.PreciseDelta.IsNil() {
+			me.SetProcessed()
+		}
+		return
+	}
+	if ly.HasScroll[mat32.Y] && ly.HasScroll[mat32.X] {
+		// fmt.Printf("ly: %v both del: %v\n", ly.Nm, del)
+		ly.ScrollActionDelta(mat32.Y, float32(del.Y))
+		ly.ScrollActionDelta(mat32.X, float32(del.X))
+		me.SetProcessed()
+	} else if ly.HasScroll[mat32.Y] {
+		// fmt.Printf("ly: %v y del: %v\n", ly.Nm, del)
+		ly.ScrollActionDelta(mat32.Y, float32(del.Y))
+		if del.X != 0 {
+			me.Delta.Y = 0
+		} else {
+			me.SetProcessed()
+		}
+	} else if ly.HasScroll[mat32.X] {
+		// fmt.Printf("ly: %v x del: %v\n", ly.Nm, del)
+		if del.X != 0 {
+			ly.ScrollActionDelta(mat32.X, float32(del.X))
+			if del.Y != 0 {
+				me.Delta.X = 0
+			} else {
+				me.SetProcessed()
+			}
+		}
+	}
+}
+
+// InsertChildAt inserts given child at given index and triggers a full
+// re-render of the layout, which re-lays out its children within its current
+// allocation, without re-doing the layout of its parents.  The current
+// element at the top of the stack is preserved for a Stacked layout, as are
+// the scroll positions (scrollbars retain their values across layouts,
+// subject to the new content size).  All of the children are laid out again,
+// so for a row or column aligned at its start, the elements before idx keep
+// their positions, and those after it shift by the size of the new child.
+func (ly *Layout) InsertChildAt(idx int, child Node2D) error {
+	updt := ly.UpdateStart()
+	defer ly.UpdateEnd(updt)
+	if err := ly.InsertChild(child, idx); err != nil {
+		return err
+	}
+	if ly.Lay == LayoutStacked && idx <= ly.StackTop && ly.NumChildren() > 1 {
+		ly.StackTop++
+	}
+	ly.SetFullReRender()
+	return nil
+}
+
+// Clear destroys all of the children of the layout, and resets the state
+// that refers to them: StackTop is reset to 0 (as for a new layout), the
+// scrollbars are deleted, and the ChildSize, ExtraSize and other cached
+// layout data are zeroed, so the layout is ready for fresh AddChild calls.
+func (ly *Layout) Clear() {
+	updt := ly.UpdateStart()
+	defer ly.UpdateEnd(updt)
+	ly.DeleteChildren(ki.DestroyKids)
+	ly.StackTop = 0
+	for d := mat32.X; d <= mat32.Y; d++ {
+		ly.DeleteScroll(d)
+		ly.HasScroll[d] = false
+	}
+	ly.UpdateScrollBarsOn()
+	ly.ChildSize = mat32.Vec2Zero
+	ly.ExtraSize = mat32.Vec2Zero
+	ly.GridSize = image.ZP
+	ly.GridCache = GridSizeCache{}
+	ly.AutoFitCols = 0
+	ly.FlowBreaks = nil
+	ly.FocusNameLast = nil
+	ly.SetFullReRender()
+}
+
+// RebuildPreservingFocus calls given function to rebuild the children of
+// the layout, e.g., with Clear and then adding new children, and restores
+// the keyboard focus to the new widget at the same path within the layout
+// (by name) as the one that had the focus before, if any -- so focus is not
+// lost when an equivalent widget is recreated.  If there is no such widget,
+// and the widget that had the focus was deleted, nothing has the focus.
+func (ly *Layout) RebuildPreservingFocus(fn func()) {
+	em := ly.EventMgr2D()
+	var foc ki.Ki
+	path := ""
+	if em != nil {
+		foc = em.CurFocus()
+		if foc != nil && foc.This() != nil && foc.ParentLevel(ly.This()) >= 0 {
+			path = foc.PathFrom(ly.This())
+		}
+	}
+	updt := ly.UpdateStart()
+	fn()
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+	if path != "" {
+		if nf := ly.FindPath(path); nf != nil {
+			if _, ni := KiToNode2D(nf); ni != nil && ni.CanFocus() {
+				em.SetFocus(nf)
+				return
+			}
+		}
+	}
+	if foc != nil && (foc.This() == nil || foc.IsDeleted() || foc.IsDestroyed()) {
+		em.SetFocus(nil)
+	}
+}
+
+// SetLayout sets the type of layout, e.g., to switch between a list and a
+// grid view at runtime, and clears the state specific to the previous type:
+// the GridData and other grid results when leaving LayoutGrid, the
+// FlowBreaks when leaving a flow layout, and StackTop when leaving
+// LayoutStacked.  Triggers a re-layout.
+func (ly *Layout) SetLayout(l Layouts) {
+	if ly.Lay == l {
+		return
+	}
+	updt := ly.UpdateStart()
+	if ly.Prop("lay") != nil { // otherwise re-styling would revert it
+		ly.SetProp("lay", l)
+	}
+	prv := ly.Lay
+	ly.Lay = l
+	switch prv {
+	case LayoutGrid:
+		ly.GridData = [RowColN][]GridData{}
+		ly.GridSize = image.ZP
+		ly.GridCache = GridSizeCache{}
+		ly.AutoFitCols = 0
+	case LayoutHorizFlow, LayoutVertFlow:
+		ly.FlowBreaks = nil
+	case LayoutStacked:
+		ly.StackTop = 0
+	}
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// EnsureChildCapacity reserves capacity for a total of n children, so that
+// adding up to that many children does not reallocate the Kids slice -- use
+// with BulkAdd when adding many children at once.
+func (ly *Layout) EnsureChildCapacity(n int) {
+	if cap(ly.Kids) >= n {
+		return
+	}
+	kids := make(ki.Slice, len(ly.Kids), n)
+	copy(kids, ly.Kids)
+	ly.Kids = kids
+}
+
+// BulkAdd calls fn to add children to the layout, e.g., the rows of a long
+// list, deferring all styling and layout until it returns, so the layout is
+// re-rendered in a single pass instead of once for each child added.
+func (ly *Layout) BulkAdd(fn func()) {
+	updt := ly.UpdateStart()
+	fn()
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// RaiseChild moves given child one step up in the z-order, i.e., later in
+// the list of children, so that it paints on top of (and receives events
+// before) the sibling it was previously below.  See MoveChildZ.
+func (ly *Layout) RaiseChild(child ki.Ki) error {
+	idx, ok := ly.Kids.IndexOf(child, 0)
+	if !ok {
+		return fmt.Errorf("gi.Layout: RaiseChild: %v is not a child of %v", child.Name(), ly.Path())
+	}
+	return ly.MoveChildZ(child, idx+1)
+}
+
+// LowerChild moves given child one step down in the z-order, i.e., earlier
+// in the list of children.  See MoveChildZ.
+func (ly *Layout) LowerChild(child ki.Ki) error {
+	idx, ok := ly.Kids.IndexOf(child, 0)
+	if !ok {
+		return fmt.Errorf("gi.Layout: LowerChild: %v is not a child of %v", child.Name(), ly.Path())
+	}
+	return ly.MoveChildZ(child, idx-1)
+}
+
+// RaiseToTop moves given child to the top of the z-order, i.e., the end of
+// the list of children, so that it paints last.  See MoveChildZ.
+func (ly *Layout) RaiseToTop(child ki.Ki) error {
+	return ly.MoveChildZ(child, len(ly.Kids)-1)
+}
+
+// LowerToBottom moves given child to the bottom of the z-order, i.e., the
+// start of the list of children, so that it paints first.  See MoveChildZ.
+func (ly *Layout) LowerToBottom(child ki.Ki) error {
+	return ly.MoveChildZ(child, 0)
+}
+
+// MoveChildZ moves given child to given index in the list of children,
+// which determines the order in which overlapping children are painted
+// (later on top), and thus the order in which they receive mouse events
+// (top first).  The index is clamped to the valid range.  For layouts where
+// positions do not depend on the order of the children (LayoutNil, e.g., a
+// canvas-like Frame with explicitly positioned children, and
+// LayoutStacked) it just re-renders, otherwise it also triggers a
+// re-layout.  The element at the top of the stack for a Stacked layout is
+// preserved.
+func (ly *Layout) MoveChildZ(child ki.Ki, to int) error {
+	idx, ok := ly.Kids.IndexOf(child, 0)
+	if !ok {
+		return fmt.Errorf("gi.Layout: MoveChildZ: %v is not a child of %v", child.Name(), ly.Path())
+	}
+	to = ints.MaxInt(0, ints.MinInt(to, len(ly.Kids)-1))
+	if to == idx {
+		return nil
+	}
+	updt := ly.UpdateStart()
+	defer ly.UpdateEnd(updt)
+	var top ki.Ki
+	if ly.Lay == LayoutStacked {
+		top, _ = ly.ChildTry(ly.StackTop)
+	}
+	ly.Kids.Move(idx, to)
+	switch ly.Lay {
+	case LayoutNil:
+	case LayoutStacked:
+		if top != nil {
+			ly.StackTop, _ = ly.Kids.IndexOf(top, 0)
+		}
+	default:
+		ly.SetFullReRender()
+	}
+	return nil
+}
+
+// SetColumns sets the number of columns to use in a grid layout (the
+// columns style property), and triggers a re-style and re-layout, with the
+// grid size computed anew.
+func (ly *Layout) SetColumns(n int) {
+	updt := ly.UpdateStart()
+	ly.SetProp("columns", n)
+	ly.GridSize = image.ZP
+	ly.GridCache = GridSizeCache{}
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// SetAutoFitMinWidth sets the minimum column width for a grid layout that
+// computes its number of columns from its allocated width (the
+// auto-fit-min-width property), and triggers a re-style and re-layout.
+func (ly *Layout) SetAutoFitMinWidth(w units.Value) {
+	updt := ly.UpdateStart()
+	ly.SetProp("auto-fit-min-width", w)
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// SetGridTemplateAreas sets the named areas of a grid layout, with one
+// string per row of the grid, e.g., "header header", "nav main" -- children
+// are placed into the areas by their grid-area property.  Triggers a
+// re-layout.
+func (ly *Layout) SetGridTemplateAreas(rows ...string) {
+	updt := ly.UpdateStart()
+	ly.GridTemplateAreas = rows
+	ly.GridAreas = nil
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// SetChildGridPos places given child of a grid layout at given 0-based row
+// and column, spanning given numbers of rows and columns, by setting its
+// row, col, row-span and col-span properties, and triggers a re-style and
+// re-layout.  As a row or col property of 0 means auto-placed, the
+// grid-row-start and grid-column-start lines are also set, so the first
+// row and col are placed exactly too.  Returns an error if it is not our
+// child, or if the row or col is negative or a span is less than 1.
+func (ly *Layout) SetChildGridPos(child Node2D, row, col, rowSpan, colSpan int) error {
+	if _, ok := ly.Kids.IndexOf(child, 0); !ok {
+		return fmt.Errorf("gi.Layout: SetChildGridPos: %v is not a child of %v", child.Name(), ly.Path())
+	}
+	if row < 0 || col < 0 || rowSpan < 1 || colSpan < 1 {
+		return fmt.Errorf("gi.Layout: SetChildGridPos: row: %d, col: %d must be >= 0 and row span: %d, col span: %d must be >= 1", row, col, rowSpan, colSpan)
+	}
+	updt := ly.UpdateStart()
+	child.SetProp("row", row)
+	child.SetProp("col", col)
+	child.SetProp("grid-row-start", row+1)
+	child.SetProp("grid-column-start", col+1)
+	child.SetProp("row-span", rowSpan)
+	child.SetProp("col-span", colSpan)
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+	return nil
+}
+
+// ChildAtGridPos returns the child of a grid layout whose cell covers given
+// row and column, accounting for its row and col spans, as placed in the
+// last layout -- nil if none, or if this is not a grid.  If children
+// overlap, the first one is returned.
+func (ly *Layout) ChildAtGridPos(row, col int) Node2D {
+	cols := ly.GridSize.X
+	rows := ly.GridSize.Y
+	if ly.Lay != LayoutGrid || row < 0 || col < 0 || row >= rows || col >= cols {
+		return nil
+	}
+	cells := ly.GridPlaceKids(rows, cols) // same placement as LayoutGridLay
+	for i, kid := range ly.Kids {
+		if nii, _ := KiToNode2D(kid); nii != nil && cells[i].Contains(row, col) {
+			return nii
+		}
+	}
+	return nil
+}
+
+// SetGridRowAlign sets the vertical alignment of the children within the
+// cells of given row of a grid layout, for children that do not set their
+// own vertical-align -- GridItemsUnset reverts the row to GridAlignItems.
+// Triggers a re-layout.
+func (ly *Layout) SetGridRowAlign(row int, al GridItemAligns) {
+	if row < 0 {
+		return
+	}
+	updt := ly.UpdateStart()
+	for len(ly.GridRowAligns) <= row {
+		ly.GridRowAligns = append(ly.GridRowAligns, GridItemsUnset)
+	}
+	ly.GridRowAligns[row] = al
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// GridRowAlign returns the vertical alignment of the children within the
+// cells of given row of a grid layout: the GridRowAligns of the row if set,
+// else GridAlignItems
+func (ly *Layout) GridRowAlign(row int) GridItemAligns {
+	if row >= 0 && row < len(ly.GridRowAligns) && ly.GridRowAligns[row] != GridItemsUnset {
+		return ly.GridRowAligns[row]
+	}
+	return ly.GridAlignItems
+}
+
+// SetGridTracks sets the size ranges of the columns (Col) or rows (Row) of
+// a grid layout, in order -- tracks beyond these are sized to their
+// content.  Triggers a re-layout.
+func (ly *Layout) SetGridTracks(rowcol RowCol, tracks ...GridTrack) {
+	updt := ly.UpdateStart()
+	if rowcol == Col {
+		ly.GridTemplateCols = tracks
+	} else {
+		ly.GridTemplateRows = tracks
+	}
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// LayoutBreakHysteresis is the distance in dots on either side of the
+// BreakWidth of a layout within which its layout type is kept as is, so it
+// does not switch back and forth between passes at the boundary
+var LayoutBreakHysteresis = float32(10)
+
+// SetBreakpoint sets a responsive breakpoint on the allocated width of the
+// layout, in dots: below width, the layout type is switched to below, e.g.,
+// LayoutVert, and above it to above, e.g., LayoutHoriz, with a hysteresis of
+// LayoutBreakHysteresis on either side.  A width of 0 turns it off.  The
+// type is switched during Layout2D, so it takes precedence over the lay
+// style property.  Triggers a re-layout.
+func (ly *Layout) SetBreakpoint(width float32, below, above Layouts) {
+	updt := ly.UpdateStart()
+	ly.BreakWidth = width
+	ly.BreakBelow = below
+	ly.BreakAbove = above
+	ly.BreakSide = 0
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
+}
+
+// ApplyBreakpoint switches the layout type according to the BreakWidth
+// and the current allocated width, returning true if it changed.  Within
+// the hysteresis band, the last side applied (BreakSide) is kept.
+func (ly *Layout) ApplyBreakpoint() bool {
+	if ly.BreakWidth <= 0 {
+		return false
+	}
+	w := ly.LayState.Alloc.Size.X
+	switch {
+	case w < ly.BreakWidth-LayoutBreakHysteresis:
+		ly.BreakSide = -1
+	case w > ly.BreakWidth+LayoutBreakHysteresis:
+		ly.BreakSide = 1
+	case ly.BreakSide == 0: // not yet switched
+		if w < ly.BreakWidth {
+			ly.BreakSide = -1
+		} else {
+			ly.BreakSide = 1
+		}
+	}
+	lay := ly.BreakAbove
+	if ly.BreakSide < 0 {
+		lay = ly.BreakBelow
+	}
+	if lay == ly.Lay {
+		return false
+	}
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v breakpoint width: %v switching from: %v to: %v\n", ly.Path(), w, ly.Lay, lay)
+	}
+	ly.Lay = lay
+	return true
+}
+
+// ParseGridAreas parses grid-template-areas rows into the cells covered by
+// each named area, with X = col and Y = row.  Names are separated by spaces
+// and . is an unnamed cell.  Returns an error if the rows do not all have
+// the same number of columns, or if an area is not rectangular.
+func ParseGridAreas(rows []string) (map[string]image.Rectangle, error) {
+	areas := make(map[string]image.Rectangle)
+	cells := make(map[string]int)
+	var grid [][]string
+	for ri, rs := range rows {
+		rw := strings.Fields(rs)
+		if len(rw) == 0 {
+			continue
+		}
+		if len(grid) > 0 && len(rw) != len(grid[0]) {
+			return nil, fmt.Errorf("gi.ParseGridAreas: row %d: %q has %d columns, not %d", ri, rs, len(rw), len(grid[0]))
+		}
+		row := len(grid)
+		for col, nm := range rw {
+			if nm == "." {
+				continue
+			}
+			cell := image.Rect(col, row, col+1, row+1)
+			if ar, has := areas[nm]; has {
+				areas[nm] = ar.Union(cell)
+			} else {
+				areas[nm] = cell
+			}
+			cells[nm]++
+		}
+		grid = append(grid, rw)
+	}
+	for nm, ar := range areas {
+		if cells[nm] != ar.Dx()*ar.Dy() {
+			return nil, fmt.Errorf("gi.ParseGridAreas: area %q is not rectangular", nm)
+		}
+	}
+	return areas, nil
+}
+
+// GridAreasSize returns the number of columns (X) and rows (Y) of the grid
+// defined by given grid-template-areas rows, including the unnamed . cells,
+// which are part of the explicit grid even though no area covers them.
+func GridAreasSize(rows []string) image.Point {
+	var sz image.Point
+	for _, rs := range rows {
+		if nc := len(strings.Fields(rs)); nc > 0 {
+			sz.X = ints.MaxInt(sz.X, nc)
+			sz.Y++
+		}
+	}
+	return sz
+}
+
+// GridAreasUpdate parses the GridTemplateAreas into GridAreas if they have
+// not yet been parsed, logging any error, in which case there are no areas.
+func (ly *Layout) GridAreasUpdate() {
+	if ly.GridAreas != nil || len(ly.GridTemplateAreas) == 0 {
+		return
+	}
+	areas, err := ParseGridAreas(ly.GridTemplateAreas)
+	if err != nil {
+		log.Printf("gi.Layout: %v grid-template-areas: %v\n", ly.Path(), err)
+		areas = make(map[string]image.Rectangle)
+	}
+	ly.GridAreas = areas
+}
+
+// Columns returns the number of columns to use in a grid layout, as set by
+// the columns style property -- 0 if not set.
+func (ly *Layout) Columns() int {
+	ly.StyMu.RLock()
+	defer ly.StyMu.RUnlock()
+	return ly.Sty.Layout.Columns
 }
 
-// ScrollDelta processes a scroll event.  If only one dimension is processed,
-// and there is a non-zero in other, then the consumed dimension is reset to 0
-// and the event is left unprocessed, so a higher level can consume the
-// remainder.
-func (ly *Layout) ScrollDelta(me *mouse.ScrollEvent) {
-	del := me.Delta
-	if ly.HasScroll[mat32.Y] && ly.HasScroll[mat32.X] {
-		// fmt.Printf("ly: %v both del: %v\n", ly.Nm, del)
-		ly.ScrollActionDelta(mat32.Y, float32(del.Y))
-		ly.ScrollActionDelta(mat32.X, float32(del.X))
-		me.SetProcessed()
-	} else if ly.HasScroll[mat32.Y] {
-		// fmt.Printf("ly: %v y del: %v\n", ly.Nm, del)
-		ly.ScrollActionDelta(mat32.Y, float32(del.Y))
-		if del.X != 0 {
-			me.Delta.Y = 0
-		} else {
-			me.SetProcessed()
+// ApplyStyleToChildren sets the given style properties on each of the
+// direct children of the layout, and triggers a re-style and re-layout.
+// Properties already set on a child are not overwritten, so child-specific
+// overrides are preserved -- except those set by a previous call, which are
+// recorded on each child and updated to the new values.
+func (ly *Layout) ApplyStyleToChildren(props ki.Props) {
+	updt := ly.UpdateStart()
+	for _, kid := range ly.Kids {
+		if kid == nil {
+			continue
 		}
-	} else if ly.HasScroll[mat32.X] {
-		// fmt.Printf("ly: %v x del: %v\n", ly.Nm, del)
-		if del.X != 0 {
-			ly.ScrollActionDelta(mat32.X, float32(del.X))
-			if del.Y != 0 {
-				me.Delta.X = 0
-			} else {
-				me.SetProcessed()
-			}
-		} else { // use Y instead as mouse wheels typically only have this
-			hasShift := me.HasAnyModifier(key.Shift, key.Alt) // shift or alt says: use vert for other dimension
-			if hasShift {
-				ly.ScrollActionDelta(mat32.X, float32(del.Y))
-				me.SetProcessed()
+		bulk, _ := kid.Prop("__layoutStyleProps").(map[string]bool)
+		if bulk == nil {
+			bulk = make(map[string]bool, len(props))
+		}
+		for key, val := range props {
+			if kid.Prop(key) != nil && !bulk[key] { // child override
+				continue
 			}
+			kid.SetProp(key, val)
+			bulk[key] = true
 		}
+		kid.SetProp("__layoutStyleProps", bulk)
 	}
+	ly.SetFullReRender()
+	ly.UpdateEnd(updt)
 }
 
 func (ly *Layout) Layout2DChildren(iter int) bool {
@@ -512,7 +1817,9 @@ func (ly *Layout) Layout2DChildren(iter int) bool {
 			return false
 		}
 		nii, _ := KiToNode2D(sn)
-		return nii.Layout2D(cbb, iter)
+		redo := nii.Layout2D(cbb, iter)
+		SavePosOrig(nii)
+		return redo
 	} else {
 		redo := false
 		for _, kid := range ly.Kids {
@@ -520,12 +1827,53 @@ func (ly *Layout) Layout2DChildren(iter int) bool {
 			if nii.Layout2D(cbb, iter) {
 				redo = true
 			}
+			SavePosOrig(nii)
 		}
 		return redo
 	}
 }
 
+// SavePosOrig saves the canonical, unscrolled position of given node,
+// as just computed by Layout2D, in its Alloc.PosOrig -- Move2D positions
+// are always relative to this, so that scrolling does not drift across
+// re-layouts even if the node's Layout2D did not save it.
+func SavePosOrig(nii Node2D) {
+	if nii == nil {
+		return
+	}
+	wb := nii.AsWidget()
+	if wb == nil || wb.IsField() {
+		return
+	}
+	wb.LayState.Alloc.PosOrig = wb.LayState.Alloc.Pos
+}
+
 // render the children
+// ChildAtPoint returns the topmost visible child of the layout whose
+// window bounding box contains given point, in window coordinates, going
+// through the children in the reverse of their PaintKids paint order, so
+// that the child painted on top is hit first -- nil if none.
+func (ly *Layout) ChildAtPoint(pt image.Point) Node2D {
+	kids := ly.PaintKids()
+	for i := len(kids) - 1; i >= 0; i-- {
+		nii, ni := KiToNode2D(kids[i])
+		if nii == nil || ni.IsInvisible() {
+			continue
+		}
+		wb := nii.AsWidget()
+		if wb == nil {
+			continue
+		}
+		wb.BBoxMu.RLock()
+		in := pt.In(wb.WinBBox)
+		wb.BBoxMu.RUnlock()
+		if in {
+			return nii
+		}
+	}
+	return nil
+}
+
 func (ly *Layout) Render2DChildren() {
 	if ly.Lay == LayoutStacked {
 		for i, kid := range ly.Kids {
@@ -539,12 +1887,15 @@ func (ly *Layout) Render2DChildren() {
 		}
 		// note: all nodes need to render to disconnect b/c of invisible
 	}
-	for _, kid := range ly.Kids {
+	for _, kid := range ly.PaintKids() {
 		if kid == nil {
 			continue
 		}
 		nii, _ := KiToNode2D(kid)
 		if nii != nil {
+			if ni := nii.AsWidget(); ni != nil && ni.IsHidden() {
+				ni.SetInvisible() // still render, to disconnect events
+			}
 			nii.Render2D()
 		}
 	}
@@ -636,6 +1987,189 @@ func (ly *Layout) AutoScroll(pos image.Point) bool {
 	return did
 }
 
+// LayoutKineticMu is a mutex protecting the kinetic scrolling state of
+// layouts (KineticVel, KineticTime, KineticTicker, KineticPending), which is
+// also accessed from the goroutine timing the frames
+var LayoutKineticMu sync.Mutex
+
+// LayoutKineticDecel is the default rate of deceleration of kinetic
+// scrolling, per second -- see Layout.KineticDecel
+var LayoutKineticDecel = float32(4)
+
+// LayoutKineticMinVel is the velocity, in dots per second, below which
+// kinetic scrolling stops
+var LayoutKineticMinVel = float32(20)
+
+// LayoutKineticFrameMSec is the interval between frames of kinetic
+// scrolling, in milliseconds
+var LayoutKineticFrameMSec = 16
+
+// LayoutKineticFlickMSec is the max time between the last drag event and
+// the release for the release to start kinetic scrolling -- a drag that
+// pauses before release just stops
+var LayoutKineticFlickMSec = 100
+
+// LayoutKineticFrame is the data of the custom event sent to the window for
+// each frame of kinetic scrolling of the layout, which is processed in the
+// window event loop -- see KineticFrame
+type LayoutKineticFrame struct {
+	Layout *Layout
+}
+
+// KineticDrag processes a drag event for KineticScroll: scrolls the content
+// along with the mouse, and updates the tracked KineticVel.
+func (ly *Layout) KineticDrag(me *mouse.DragEvent) {
+	ly.StopKineticScroll()
+	del := me.Delta()
+	dv := mat32.NewVec2(-float32(del.X), -float32(del.Y)) // content follows the mouse
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if !ly.HasScroll[d] {
+			dv.SetDim(d, 0)
+		}
+	}
+	ly.ScrollByPixels(dv)
+	dt := float32(me.Time().Sub(me.LastTime).Seconds())
+	LayoutKineticMu.Lock()
+	if dt > 0 {
+		// smooth over the last few events, as mouse deltas are noisy
+		ly.KineticVel = ly.KineticVel.MulScalar(0.2).Add(dv.DivScalar(dt).MulScalar(0.8))
+	}
+	ly.KineticTime = me.Time()
+	LayoutKineticMu.Unlock()
+}
+
+// KineticRelease processes the release of the mouse at the end of a drag for
+// KineticScroll: starts kinetic scrolling with the tracked velocity if the
+// release immediately follows the last drag event (a flick).
+func (ly *Layout) KineticRelease(me *mouse.Event) {
+	LayoutKineticMu.Lock()
+	vel, last := ly.KineticVel, ly.KineticTime
+	ly.KineticVel = mat32.Vec2Zero
+	ly.KineticTime = time.Time{}
+	LayoutKineticMu.Unlock()
+	if last.IsZero() || me.Time().Sub(last) > time.Duration(LayoutKineticFlickMSec)*time.Millisecond {
+		return
+	}
+	ly.StartKineticScroll(vel)
+}
+
+// KineticStep advances kinetic scrolling by given time step in seconds:
+// scrolls by the current KineticVel, and decelerates it exponentially at
+// rate KineticDecel.  The velocity in a dimension goes to 0 when it falls
+// below LayoutKineticMinVel, or when the scroll hits either end of its range.
+// Returns true if still moving.
+func (ly *Layout) KineticStep(dt float32) bool {
+	decel := ly.KineticDecel
+	if decel <= 0 {
+		decel = LayoutKineticDecel
+	}
+	LayoutKineticMu.Lock()
+	vel := ly.KineticVel
+	LayoutKineticMu.Unlock()
+	ly.ScrollByPixels(vel.MulScalar(dt)) // not locked: sends scroll signals
+	vel = vel.MulScalar(mat32.Exp(-decel * dt))
+	for d := mat32.X; d <= mat32.Y; d++ {
+		v := vel.Dim(d)
+		if v == 0 {
+			continue
+		}
+		if !ly.HasScroll[d] || mat32.Abs(v) < LayoutKineticMinVel {
+			vel.SetDim(d, 0)
+			continue
+		}
+		sc := ly.Scrolls[d]
+		if (v < 0 && sc.Value <= sc.Min) || (v > 0 && sc.Value >= sc.Max-sc.ThumbVal) {
+			vel.SetDim(d, 0)
+		}
+	}
+	LayoutKineticMu.Lock()
+	ly.KineticVel = vel
+	LayoutKineticMu.Unlock()
+	return !vel.IsNil()
+}
+
+// StartKineticScroll starts kinetic scrolling with given initial velocity,
+// in dots per second, advanced by KineticFrame every LayoutKineticFrameMSec
+// until it stops, or StopKineticScroll is called.
+func (ly *Layout) StartKineticScroll(vel mat32.Vec2) {
+	ly.StopKineticScroll()
+	if vel.IsNil() {
+		return
+	}
+	win := ly.ParentWindow()
+	if win == nil {
+		return
+	}
+	tick := time.NewTicker(time.Duration(LayoutKineticFrameMSec) * time.Millisecond)
+	LayoutKineticMu.Lock()
+	ly.KineticVel = vel
+	ly.KineticTime = time.Now()
+	ly.KineticTicker = tick
+	LayoutKineticMu.Unlock()
+	go ly.KineticAnim(win, tick)
+}
+
+// StopKineticScroll stops any kinetic scrolling in progress -- the
+// goroutine timing it exits on its next frame.
+func (ly *Layout) StopKineticScroll() {
+	LayoutKineticMu.Lock()
+	ly.KineticTicker = nil
+	ly.KineticVel = mat32.Vec2Zero
+	LayoutKineticMu.Unlock()
+}
+
+// KineticAnim times the frames of kinetic scrolling with given ticker: it
+// does not touch the layout itself, but sends a LayoutKineticFrame custom
+// event to the window for each frame, once the previous one has been
+// processed, until the ticker is no longer the KineticTicker of the layout.
+func (ly *Layout) KineticAnim(win *Window, tick *time.Ticker) {
+	defer tick.Stop()
+	for range tick.C {
+		LayoutKineticMu.Lock()
+		if ly.KineticTicker != tick {
+			LayoutKineticMu.Unlock()
+			return // stopped or restarted
+		}
+		if win.IsClosed() {
+			ly.KineticTicker = nil
+			LayoutKineticMu.Unlock()
+			return
+		}
+		send := !ly.KineticPending
+		ly.KineticPending = true
+		LayoutKineticMu.Unlock()
+		if send {
+			win.SendCustomEvent(&LayoutKineticFrame{Layout: ly})
+		}
+	}
+}
+
+// KineticFrame advances kinetic scrolling by the time since the last frame,
+// within a window update -- called in the window event loop upon receiving
+// the LayoutKineticFrame event sent by KineticAnim.
+func (ly *Layout) KineticFrame() {
+	LayoutKineticMu.Lock()
+	ly.KineticPending = false
+	if ly.KineticTicker == nil {
+		LayoutKineticMu.Unlock()
+		return
+	}
+	now := time.Now()
+	dt := float32(now.Sub(ly.KineticTime).Seconds())
+	ly.KineticTime = now
+	LayoutKineticMu.Unlock()
+	if ly.This() == nil || ly.IsDeleted() || ly.IsDestroyed() {
+		ly.StopKineticScroll()
+		return
+	}
+	wupdt := ly.TopUpdateStart()
+	moving := ly.KineticStep(dt)
+	ly.TopUpdateEnd(wupdt)
+	if !moving {
+		ly.StopKineticScroll()
+	}
+}
+
 // ScrollToBoxDim scrolls to ensure that given rect box along one dimension is
 // in view -- returns true if scrolling was needed
 func (ly *Layout) ScrollToBoxDim(dim mat32.Dims, minBox, maxBox int) bool {
@@ -698,6 +2232,50 @@ func (ly *Layout) ScrollToItem(ni Node2D) bool {
 	return ly.ScrollToBox(ni.AsNode2D().ObjBBox)
 }
 
+// ChildScrolledBBox returns the bounding box of the given child of the
+// layout, in the same coordinates as ChildrenBBox2D, computed from its
+// allocated relative position and size, accounting for the current scroll
+// offsets -- it does not depend on the child having been moved.
+func (ly *Layout) ChildScrolledBBox(ni *WidgetBase) image.Rectangle {
+	pos := ly.LayState.Alloc.Pos.Add(ni.LayState.Alloc.PosRel)
+	return LayoutRect(pos, ni.LayState.Alloc.Size).Add(ly.Move2DDelta(image.Point{}))
+}
+
+// NumVisibleChildren returns the number of children that are fully visible
+// within the children bounding box of the layout, given the current scroll
+// offsets.
+func (ly *Layout) NumVisibleChildren() int {
+	cbb := ly.This().(Node2D).ChildrenBBox2D()
+	n := 0
+	for _, kid := range ly.Kids {
+		ni := kid.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		if ly.ChildScrolledBBox(ni).In(cbb) {
+			n++
+		}
+	}
+	return n
+}
+
+// FirstVisibleChildIdx returns the index of the first child that is fully
+// visible within the children bounding box of the layout, given the current
+// scroll offsets -- returns -1 if none are visible.
+func (ly *Layout) FirstVisibleChildIdx() int {
+	cbb := ly.This().(Node2D).ChildrenBBox2D()
+	for i, kid := range ly.Kids {
+		ni := kid.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		if ly.ChildScrolledBBox(ni).In(cbb) {
+			return i
+		}
+	}
+	return -1
+}
+
 // ScrollDimToStart scrolls to put the given child coordinate position (eg.,
 // top / left of a view box) at the start (top / left) of our scroll area, to
 // the extent possible -- returns true if scrolling was needed.
@@ -1033,6 +2611,40 @@ func (ly *Layout) LayoutScrollEvents() {
 		li := recv.Embed(KiT_Layout).(*Layout)
 		li.ScrollDelta(me)
 	})
+	// LowPri so draggable children capture drags first
+	ly.ConnectEvent(oswin.MouseDragEvent, LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.DragEvent)
+		li := recv.Embed(KiT_Layout).(*Layout)
+		if !li.KineticScroll || me.Button != mouse.Left {
+			return
+		}
+		me.SetProcessed()
+		li.KineticDrag(me)
+	})
+	if ly.KineticScroll {
+		ly.ConnectEvent(oswin.CustomEventType, RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+			ce := d.(*oswin.CustomEvent)
+			li := recv.Embed(KiT_Layout).(*Layout)
+			if kf, ok := ce.Data.(*LayoutKineticFrame); ok && kf.Layout == li {
+				ce.SetProcessed()
+				li.KineticFrame()
+			}
+		})
+	}
+	// does NOT consume event: a press just stops any kinetic scrolling
+	ly.ConnectEvent(oswin.MouseEvent, LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.Event)
+		li := recv.Embed(KiT_Layout).(*Layout)
+		if !li.KineticScroll || me.Button != mouse.Left {
+			return
+		}
+		switch me.Action {
+		case mouse.Press:
+			li.StopKineticScroll()
+		case mouse.Release:
+			li.KineticRelease(me)
+		}
+	})
 	// HiPri to do it first so others can be in view etc -- does NOT consume event!
 	ly.ConnectEvent(oswin.DNDMoveEvent, HiPri, func(recv, send ki.Ki, sig int64, d interface{}) {
 		me := d.(*dnd.MoveEvent)
@@ -1058,6 +2670,132 @@ func (ly *Layout) KeyChordEvent() {
 	})
 }
 
+// OverflowingChildren returns the children whose Need size exceeds their
+// allocated size in either dimension, as of the last layout -- for a Grid
+// layout, the allocated size is that of the grid cell(s) the child occupies.
+// Such children are clipped, so this is useful for debugging layouts that
+// are too small, e.g., a grid with fixed-size tracks.
+func (ly *Layout) OverflowingChildren() []Node2D {
+	var ovf []Node2D
+	var cells []GridCell
+	if ly.Lay == LayoutGrid {
+		cells = ly.GridPlaceKids(ly.GridSize.Y, ly.GridSize.X) // same placement as LayoutGridLay
+	}
+	for i, kid := range ly.Kids {
+		nii, _ := KiToNode2D(kid)
+		if nii == nil {
+			continue
+		}
+		ni := nii.AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		alloc := ni.LayState.Alloc.Size
+		if cells != nil && cells[i].RowSpan > 0 {
+			gc := cells[i]
+			alloc.X = ly.GridSpanAlloc(Col, gc.Col, gc.ColSpan)
+			alloc.Y = ly.GridSpanAlloc(Row, gc.Row, gc.RowSpan)
+		}
+		need := ni.LayState.Size.Need
+		if need.X > alloc.X+0.01 || need.Y > alloc.Y+0.01 {
+			ovf = append(ovf, nii)
+		}
+	}
+	return ovf
+}
+
+///////////////////////////////////////////////////
+//   Anchoring, e.g., for popups
+
+// AnchorBounds returns the window-level bounds within which a layout
+// anchored to given target must fit: the window viewport if the target is
+// in a window, else the top-level viewport of the target.
+func AnchorBounds(target Node2D) image.Rectangle {
+	vp := target.AsNode2D().ViewportSafe()
+	if vp == nil {
+		return image.ZR
+	}
+	if vp.Win != nil && vp.Win.Viewport != nil {
+		vp = vp.Win.Viewport
+	}
+	for vp.ViewportSafe() != nil && vp.ViewportSafe() != vp {
+		vp = vp.ViewportSafe()
+	}
+	vp.BBoxMu.RLock()
+	defer vp.BBoxMu.RUnlock()
+	return vp.WinBBox
+}
+
+// AnchorTo sets the allocated position of the layout (e.g., the Frame of a
+// popup) in window coordinates, relative to the WinBBox of the given target
+// node, on the given side of it: AlignBottom places it below the target,
+// aligned with its left edge, AlignTop above it, and AlignLeft / AlignRight
+// to its left / right, aligned with its top edge.  If flip is set and the
+// layout would extend beyond the window bounds on that side, it is flipped
+// to the opposite side, if it fits there.  The layout is then shifted along the edge of the
+// target as needed to remain within the bounds.  Uses the allocated size if
+// set, otherwise the preferred size.
+func (ly *Layout) AnchorTo(target Node2D, side gist.Align, flip bool) {
+	tb := target.AsNode2D().WinBBox
+	bnds := AnchorBounds(target)
+	sz := ly.LayState.Alloc.Size
+	if sz.IsNil() {
+		sz = ly.LayState.Size.Pref
+	}
+	isz := sz.ToPointCeil()
+
+	pos := anchorPos(tb, isz, side)
+	if flip && !bnds.Empty() && !anchorFits(pos, isz, side, bnds) {
+		opp := gist.AlignTop
+		switch side {
+		case gist.AlignTop:
+			opp = gist.AlignBottom
+		case gist.AlignLeft:
+			opp = gist.AlignRight
+		case gist.AlignRight:
+			opp = gist.AlignLeft
+		}
+		if fpos := anchorPos(tb, isz, opp); anchorFits(fpos, isz, opp, bnds) {
+			pos = fpos
+		}
+	}
+	if !bnds.Empty() { // keep within bounds along the edge of the target
+		switch side {
+		case gist.AlignLeft, gist.AlignRight:
+			pos.Y = ints.MaxInt(bnds.Min.Y, ints.MinInt(pos.Y, bnds.Max.Y-isz.Y))
+		default:
+			pos.X = ints.MaxInt(bnds.Min.X, ints.MinInt(pos.X, bnds.Max.X-isz.X))
+		}
+	}
+	ly.LayState.Alloc.Pos = mat32.NewVec2FmPoint(pos)
+}
+
+// anchorFits returns true if a box of given size at given position fits
+// within bounds along the dimension perpendicular to the given side
+func anchorFits(pos, sz image.Point, side gist.Align, bnds image.Rectangle) bool {
+	switch side {
+	case gist.AlignLeft, gist.AlignRight:
+		return pos.X >= bnds.Min.X && pos.X+sz.X <= bnds.Max.X
+	default:
+		return pos.Y >= bnds.Min.Y && pos.Y+sz.Y <= bnds.Max.Y
+	}
+}
+
+// anchorPos returns the position of a box of given size on given side of
+// target box -- any side other than Top, Left, Right is treated as Bottom.
+func anchorPos(tb image.Rectangle, sz image.Point, side gist.Align) image.Point {
+	switch side {
+	case gist.AlignTop:
+		return image.Pt(tb.Min.X, tb.Min.Y-sz.Y)
+	case gist.AlignLeft:
+		return image.Pt(tb.Min.X-sz.X, tb.Min.Y)
+	case gist.AlignRight:
+		return image.Pt(tb.Max.X, tb.Min.Y)
+	default:
+		return image.Pt(tb.Min.X, tb.Max.Y)
+	}
+}
+
 ///////////////////////////////////////////////////
 //   Standard Node2D interface
 
@@ -1078,16 +2816,36 @@ func (ly *Layout) ComputeBBox2D(parBBox image.Rectangle, delta image.Point) {
 }
 
 func (ly *Layout) ChildrenBBox2D() image.Rectangle {
-	nb := ly.ChildrenBBox2DWidget()
-	nb.Max.X -= int(ly.ExtraSize.X)
-	nb.Max.Y -= int(ly.ExtraSize.Y)
+	nb := ly.ChildrenObjBBox()
+	// exclude the region of each scrollbar that is present, on the side where
+	// it is docked: the vertical bar takes up width on the right (or left),
+	// and the horizontal bar takes up height on the bottom (or top).
+	// extents are rounded up so the clip never overlaps a fractional bar.
+	if ly.HasScroll[mat32.Y] {
+		if ly.VScrollLeft {
+			nb.Min.X += int(mat32.Ceil(ly.ExtraSize.X))
+		} else {
+			nb.Max.X -= int(mat32.Ceil(ly.ExtraSize.X))
+		}
+	}
+	if ly.HasScroll[mat32.X] {
+		if ly.HScrollTop {
+			nb.Min.Y += int(mat32.Ceil(ly.ExtraSize.Y))
+		} else {
+			nb.Max.Y -= int(mat32.Ceil(ly.ExtraSize.Y))
+		}
+	}
+	nb = nb.Intersect(ly.VpBBox) // after the scrollbars, which are at our own edges
+	if ly.ClipModifier != nil {
+		nb = ly.ClipModifier(nb)
+	}
 	return nb
 }
 
 // StyleFromProps styles Layout-specific fields from ki.Prop properties
 // doesn't support inherit or default
 func (ly *Layout) StyleFromProps(props ki.Props, vp *Viewport2D) {
-	keys := []string{"lay", "spacing"}
+	keys := []string{"lay", "spacing", "min-thumb-size", "h-scroll-step", "v-scroll-step", "auto-fit-min-width", "min-columns", "max-columns", "grid-template-areas", "stretch-mode", "grid-justify-items", "grid-align-items", "grid-template-columns", "grid-template-rows"}
 	for _, key := range keys {
 		val, has := props[key]
 		if !has {
@@ -1109,6 +2867,80 @@ func (ly *Layout) StyleFromProps(props ki.Props, vp *Viewport2D) {
 			}
 		case "spacing":
 			ly.Spacing.SetIFace(val, key)
+		case "min-thumb-size":
+			ly.MinThumbSize.SetIFace(val, key)
+		case "h-scroll-step":
+			ly.HScrollStep.SetIFace(val, key)
+		case "v-scroll-step":
+			ly.VScrollStep.SetIFace(val, key)
+		case "auto-fit-min-width":
+			ly.AutoFitMin.SetIFace(val, key)
+		case "min-columns":
+			if iv, ok := kit.ToInt(val); ok {
+				ly.MinColumns = int(iv)
+			}
+		case "max-columns":
+			if iv, ok := kit.ToInt(val); ok {
+				ly.MaxColumns = int(iv)
+			}
+		case "stretch-mode":
+			switch vt := val.(type) {
+			case string:
+				kit.Enums.SetAnyEnumIfaceFromString(&ly.StretchMode, vt)
+			case StretchModes:
+				ly.StretchMode = vt
+			default:
+				if iv, ok := kit.ToInt(val); ok {
+					ly.StretchMode = StretchModes(iv)
+				} else {
+					gist.StyleSetError(key, val)
+				}
+			}
+		case "grid-justify-items", "grid-align-items":
+			ga := &ly.GridJustifyItems
+			if key == "grid-align-items" {
+				ga = &ly.GridAlignItems
+			}
+			switch vt := val.(type) {
+			case string:
+				kit.Enums.SetAnyEnumIfaceFromString(ga, vt)
+			case GridItemAligns:
+				*ga = vt
+			default:
+				if iv, ok := kit.ToInt(val); ok {
+					*ga = GridItemAligns(iv)
+				} else {
+					gist.StyleSetError(key, val)
+				}
+			}
+		case "grid-template-columns", "grid-template-rows":
+			tracks := &ly.GridTemplateCols
+			if key == "grid-template-rows" {
+				tracks = &ly.GridTemplateRows
+			}
+			switch vt := val.(type) {
+			case []GridTrack:
+				*tracks = vt
+			default:
+				trs, err := ParseGridTracks(kit.ToString(val))
+				if err != nil {
+					log.Printf("gi.Layout: %v %v: %v\n", ly.Path(), key, err)
+				} else {
+					*tracks = trs
+				}
+			}
+		case "grid-template-areas":
+			var tmpl []string
+			switch vt := val.(type) {
+			case []string:
+				tmpl = vt
+			default:
+				tmpl = strings.Split(kit.ToString(val), "/")
+			}
+			if strings.Join(tmpl, "/") != strings.Join(ly.GridTemplateAreas, "/") {
+				ly.GridTemplateAreas = tmpl
+				ly.GridAreas = nil
+			}
 		}
 	}
 }
@@ -1116,6 +2948,10 @@ func (ly *Layout) StyleFromProps(props ki.Props, vp *Viewport2D) {
 // ToDots runs ToDots on unit values, to compile down to raw pixels
 func (ly *Layout) StyleToDots(uc *units.Context) {
 	ly.Spacing.ToDots(uc)
+	ly.MinThumbSize.ToDots(uc)
+	ly.HScrollStep.ToDots(uc)
+	ly.VScrollStep.ToDots(uc)
+	ly.AutoFitMin.ToDots(uc)
 }
 
 // StyleLayout does layout styling -- it sets the StyMu Lock
@@ -1152,14 +2988,132 @@ func (ly *Layout) Style2D() {
 
 func (ly *Layout) Size2D(iter int) {
 	ly.InitLayout2D()
-	switch ly.Lay {
-	case LayoutHorizFlow, LayoutVertFlow:
-		GatherSizesFlow(ly, iter)
-	case LayoutGrid:
-		GatherSizesGrid(ly)
-	default:
-		GatherSizes(ly)
+	if LayoutProfile {
+		st := time.Now()
+		GatherSizesLay(ly, iter)
+		LayoutProf.AddSize2D(ly, time.Since(st))
+		return
+	}
+	GatherSizesLay(ly, iter)
+}
+
+// MeasureOnly returns the preferred size that the layout would have based
+// on the current sizes of its children, as computed in Size2D, without
+// changing the layout state of the layout or its children -- e.g., for
+// previewing the size of a set of children.  The children must have already
+// been sized in Size2D -- their current size preferences are used as-is.
+func (ly *Layout) MeasureOnly() mat32.Vec2 {
+	lst := ly.LayState
+	gsz := ly.GridSize
+	var gd [RowColN][]GridData
+	for i := range gd {
+		gd[i] = ly.GridData[i]
+		ly.GridData[i] = nil // temporary, so gathering does not overwrite
+	}
+	fb := ly.FlowBreaks
+	gc := ly.GridCache
+	ly.GridCache = GridSizeCache{} // likewise for the cache of the grid data
+	kst := make([]LayoutState, len(ly.Kids))
+	for i, kid := range ly.Kids {
+		if ni := kid.(Node2D).AsWidget(); ni != nil {
+			kst[i] = ni.LayState
+		}
+	}
+
+	ly.InitLayout2D()
+	GatherSizesLay(ly, 0)
+	pref := ly.LayState.Size.Pref
+
+	ly.LayState = lst
+	ly.GridSize = gsz
+	ly.GridData = gd
+	ly.FlowBreaks = fb
+	ly.GridCache = gc
+	for i, kid := range ly.Kids {
+		if ni := kid.(Node2D).AsWidget(); ni != nil {
+			ni.LayState = kst[i]
+		}
+	}
+	return pref
+}
+
+// MinContentSize returns the minimum size that would fit all of the content
+// of the layout without clipping, regardless of its current allocation:
+// the MinContentSize of the children, summed along the layout dimension
+// (with spacing) and maxed along the other, per track for grids, per region
+// for Border, and maxed for Stacked and Nil layouts -- flow layouts are assumed to wrap every
+// element.  Elements spanning multiple grid tracks are counted in their
+// first track.  Includes the box space and min size style of the layout.
+// Reads the sizes from the last Size2D pass, without changing any state.
+func (ly *Layout) MinContentSize() mat32.Vec2 {
+	var sz mat32.Vec2
+	if ly.Lay == LayoutGrid {
+		sz = ly.MinContentSizeGrid()
+	} else {
+		var sum, max mat32.Vec2
+		for _, c := range ly.Kids {
+			if c == nil {
+				continue
+			}
+			nii := c.(Node2D)
+			if ni := nii.AsWidget(); ni == nil || ni.IsCollapsed() {
+				continue
+			}
+			ksz := nii.MinContentSize()
+			sum.SetAdd(ksz)
+			max.SetMax(ksz)
+		}
+		elspc := float32(ints.MaxInt(NumShownKids(ly)-1, 0)) * ly.Spacing.Dots
+		switch ly.Lay {
+		case LayoutBorder:
+			sz = BorderSizes(ly, func(nii Node2D, ni *WidgetBase) mat32.Vec2 {
+				return nii.MinContentSize()
+			})
+		case LayoutHoriz, LayoutVertFlow:
+			sz.Set(sum.X+elspc, max.Y)
+		case LayoutVert, LayoutHorizFlow:
+			sz.Set(max.X, sum.Y+elspc)
+		default:
+			sz = max
+		}
+	}
+	sz.SetAddScalar(2.0 * ly.BoxSpace())
+	return sz.Max(ly.Sty.Layout.MinSizeDots())
+}
+
+// MinContentSizeGrid returns the minimum size of the tracks of a grid
+// layout, using the GridSize of the last Size2D pass -- see MinContentSize.
+func (ly *Layout) MinContentSizeGrid() mat32.Vec2 {
+	cols, rows := ly.GridSize.X, ly.GridSize.Y
+	if cols == 0 || rows == 0 {
+		return mat32.Vec2Zero
 	}
+	colsz := make([]float32, cols)
+	rowsz := make([]float32, rows)
+	cells := ly.GridPlaceKids(rows, cols)
+	for i, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		nii := c.(Node2D)
+		ni := nii.AsWidget()
+		if ni == nil || cells[i].RowSpan == 0 || ni.IsCollapsed() {
+			continue
+		}
+		ksz := nii.MinContentSize()
+		mat32.SetMax(&colsz[cells[i].Col], ksz.X)
+		mat32.SetMax(&rowsz[cells[i].Row], ksz.Y)
+	}
+	var sz mat32.Vec2
+	for _, cs := range colsz {
+		sz.X += cs
+	}
+	for _, rs := range rowsz {
+		sz.Y += rs
+	}
+	sz.X += float32(cols-1) * ly.Spacing.Dots
+	sz.Y += float32(rows-1) * ly.Spacing.Dots
+	return sz
 }
 
 func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
@@ -1168,9 +3122,25 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 	//		fmt.Printf("Layout: %v Iteration: %v  NeedsRedo: %v\n", ly.Path(), iter, ly.NeedsRedo)
 	//	}
 	//}
+	if LayoutProfile {
+		LayoutProf.AddLayout2D(ly)
+	}
 	LayAllocFromParent(ly)               // in case we didn't get anything
 	ly.Layout2DBase(parBBox, true, iter) // init style
+	if ly.LayState.Alloc.Size.IsNil() {  // not sized yet -- defer until we are
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Layout: %v zero alloc size, deferring layout\n", ly.Path())
+		}
+		return false
+	}
+	ly.CheckResize()
+	if iter == 0 && ly.ApplyBreakpoint() { // sizes were gathered for the old type
+		ly.NeedsRedo = true
+		return true
+	}
 	redo := false
+	sbs := ly.PredictScrolls() // reserve the space of known scrollbars
+	ly.LayState.Alloc.Size.SetSub(sbs)
 	switch ly.Lay {
 	case LayoutHoriz:
 		LayoutAlongDim(ly, mat32.X)
@@ -1179,10 +3149,21 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 		LayoutAlongDim(ly, mat32.Y)
 		LayoutSharedDim(ly, mat32.X)
 	case LayoutGrid:
+		if ly.AutoFitColumns() && iter == 0 { // sizes were gathered for the old columns
+			ly.LayState.Alloc.Size.SetAdd(sbs)
+			ly.NeedsRedo = true
+			return true
+		}
 		LayoutGridLay(ly)
 	case LayoutStacked:
-		LayoutSharedDim(ly, mat32.X)
-		LayoutSharedDim(ly, mat32.Y)
+		if ly.FillStack {
+			LayoutStackedFill(ly)
+		} else {
+			LayoutSharedDim(ly, mat32.X)
+			LayoutSharedDim(ly, mat32.Y)
+		}
+	case LayoutBorder:
+		LayoutBorderRegions(ly)
 	case LayoutHorizFlow:
 		redo = LayoutFlow(ly, mat32.X, iter)
 	case LayoutVertFlow:
@@ -1190,6 +3171,12 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 	case LayoutNil:
 		// nothing
 	}
+	if ly.Lay != LayoutNil && LayoutAspect(ly) && iter == 0 { // heights were gathered for the old widths
+		ly.LayState.Alloc.Size.SetAdd(sbs)
+		ly.NeedsRedo = true
+		return true
+	}
+	ly.LayState.Alloc.Size.SetAdd(sbs)
 	ly.FinalizeLayout()
 	if redo && iter == 0 {
 		ly.NeedsRedo = true
@@ -1204,19 +3191,92 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 		if delta != image.ZP {
 			ly.Move2DChildren(delta) // move is a separate step
 		}
+		ly.UpdateScrollBarsOn() // layout is complete
 	}
 	return ly.NeedsRedo
 }
 
-// we add our own offset here
+// IsLayoutStable returns true if the layout has settled, with no further
+// layout passes pending: neither it nor any of its descendants needs a redo
+// or a full re-render, none of their size units changed with the final
+// layout sizes (LayoutState.UnitsChanged, e.g., for percentages), and the
+// allocations of the descendants are the same as in the previous pass, if
+// any, within LayoutEqualTol -- e.g., for tests to wait for it to settle.
+func (ly *Layout) IsLayoutStable() bool {
+	if ly.IsUpdating() {
+		return false
+	}
+	stable := true
+	ly.FuncDownMeFirst(0, ly.This(), func(k ki.Ki, level int, d interface{}) bool {
+		nii, ni := KiToNode2D(k)
+		if nii == nil || ni.IsDeleted() || ni.IsDestroyed() || !stable {
+			return ki.Break
+		}
+		if ni.NeedsFullReRender() {
+			stable = false
+			return ki.Break
+		}
+		if cly := nii.AsLayout2D(); cly != nil && cly.NeedsRedo {
+			stable = false
+			return ki.Break
+		}
+		wb := nii.AsWidget()
+		if wb == nil {
+			return ki.Continue
+		}
+		ld := &wb.LayState
+		if ld.UnitsChanged || (level > 0 && ld.Prev.Size != mat32.Vec2Zero && ld.AllocChanged()) {
+			stable = false
+			return ki.Break
+		}
+		return ki.Continue
+	})
+	return stable
+}
+
+// Relayout is a lighter version of ReRender2DTree for changes that only
+// affect sizing: it redoes the Size2D and Layout2D passes (including
+// recomputing the scrollbars) and re-renders, but skips the Init2D and
+// Style2D passes.  It is only safe when the styles are still valid, e.g.,
+// the content of a child changed size, or computed sizes in Sty.Layout were
+// updated directly -- any change to style properties (e.g., via SetProp)
+// requires the full ReRender2DTree to take effect.
+func (ly *Layout) Relayout() {
+	parBBox := image.ZR
+	pni, _ := KiToNode2D(ly.Par)
+	if pni != nil {
+		parBBox = pni.ChildrenBBox2D()
+	}
+	delta := ly.LayState.Alloc.Pos.Sub(ly.LayState.Alloc.PosOrig)
+	ly.LayState.Alloc.Pos = ly.LayState.Alloc.PosOrig
+	ld := ly.LayState // save our current layout data
+	updt := ly.UpdateStart()
+	ly.Size2DTree(0)
+	ly.LayState = ld // restore
+	ly.Layout2DTree()
+	if !delta.IsNil() {
+		ly.Move2D(LayoutRoundPoint(delta), parBBox)
+	}
+	ly.Render2DTree()
+	ly.UpdateEndNoSig(updt)
+}
+
+// we add our own offset here -- content is also shifted past any scrollbar
+// docked at the start
 func (ly *Layout) Move2DDelta(delta image.Point) image.Point {
 	if ly.HasScroll[mat32.X] {
 		off := ly.Scrolls[mat32.X].Value
-		delta.X -= int(off)
+		delta.X -= LayoutRoundDots(off)
+		if ly.HScrollTop {
+			delta.Y += LayoutRoundDots(ly.ExtraSize.Y)
+		}
 	}
 	if ly.HasScroll[mat32.Y] {
 		off := ly.Scrolls[mat32.Y].Value
-		delta.Y -= int(off)
+		delta.Y -= LayoutRoundDots(off)
+		if ly.VScrollLeft {
+			delta.X += LayoutRoundDots(ly.ExtraSize.X)
+		}
 	}
 	return delta
 }
@@ -1238,8 +3298,10 @@ func (ly *Layout) Render2D() {
 		if ly.ScrollsOff {
 			ly.ManageOverflow()
 		}
-		ly.RenderScrolls()
-		ly.Render2DChildren()
+		ly.RenderContent()
+		if DebugLayoutBounds {
+			ly.RenderDebugBounds()
+		}
 		ly.PopBounds()
 	} else {
 		ly.SetScrollsOff()
@@ -1266,7 +3328,9 @@ func (ly *Layout) HasFocus2D() bool {
 
 // Stretch adds an infinitely stretchy element for spacing out layouts
 // (max-size = -1) set the width / height property to determine how much it
-// takes relative to other stretchy elements
+// takes relative to other stretchy elements -- set min-width / min-height
+// to keep it from shrinking below a given size when space is tight, as need
+// (min) is a hard floor for all elements in a layout
 type Stretch struct {
 	WidgetBase
 }
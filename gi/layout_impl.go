@@ -5,9 +5,10 @@
 package gi
 
 import (
-	"fmt"
+	"sort"
 
 	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
 	"github.com/goki/ki/ints"
 	"github.com/goki/ki/ki"
 	"github.com/goki/mat32"
@@ -43,6 +44,89 @@ func LaySummedDim(ly Layouts) mat32.Dims {
 // second me-first Layout2D pass: each layout allocates AllocSize for its
 // children based on aggregated size data, and so on down the tree
 
+// VisualKids returns the children of the layout in their visual order, for
+// row and column layouts (LayoutHoriz, LayoutVert): sorted by the order
+// style property, stably so that equal values keep the tree order.  Returns
+// Kids itself if no child sets an order, or for any other type of layout.
+func (ly *Layout) VisualKids() ki.Slice {
+	if ly.Lay != LayoutHoriz && ly.Lay != LayoutVert {
+		return ly.Kids
+	}
+	return SortedKids(ly.Kids, func(lst *gist.Layout) int { return lst.Order })
+}
+
+// PaintKids returns the children of the layout in the order in which they
+// are painted, from back to front: the VisualKids order (reversed for
+// ReverseRenderOrder), sorted stably by the z-index style property.
+// Hit-testing goes in the reverse of this order, so the topmost child is
+// hit first (see ChildAtPoint).
+func (ly *Layout) PaintKids() ki.Slice {
+	kids := ly.VisualKids()
+	if ly.ReverseRenderOrder {
+		rev := make(ki.Slice, len(kids))
+		for i, k := range kids {
+			rev[len(kids)-1-i] = k
+		}
+		kids = rev
+	}
+	return SortedKids(kids, func(lst *gist.Layout) int { return lst.ZIndex })
+}
+
+// SortedKids returns the given children sorted stably by the given int
+// layout style value, or kids itself if no child has a nonzero value.
+func SortedKids(kids ki.Slice, val func(lst *gist.Layout) int) ki.Slice {
+	vals := make([]int, len(kids))
+	sorted := false
+	for i, c := range kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ni.StyMu.RLock()
+		vals[i] = val(&ni.Sty.Layout)
+		ni.StyMu.RUnlock()
+		if vals[i] != 0 {
+			sorted = true
+		}
+	}
+	if !sorted {
+		return kids
+	}
+	idxs := make([]int, len(kids))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return vals[idxs[i]] < vals[idxs[j]]
+	})
+	skids := make(ki.Slice, len(kids))
+	for i, ix := range idxs {
+		skids[i] = kids[ix]
+	}
+	return skids
+}
+
+// NumShownKids returns the number of children of the layout that are not
+// collapsed (see WidgetBase.IsCollapsed), which are the ones that take up
+// space, including spacing between elements.
+func NumShownKids(ly *Layout) int {
+	n := 0
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
 // GatherSizesSumMax gets basic sum and max data across all kiddos
 func GatherSizesSumMax(ly *Layout) (sumPref, sumNeed, maxPref, maxNeed mat32.Vec2) {
 	sz := len(ly.Kids)
@@ -75,18 +159,34 @@ func GatherSizesSumMax(ly *Layout) (sumPref, sumNeed, maxPref, maxNeed mat32.Vec
 			continue
 		}
 		ni.LayState.UpdateSizes()
+		if ni.IsCollapsed() {
+			ni.LayState.Size = gist.SizePrefs{}
+			continue
+		}
 		sumNeed = sumNeed.Add(ni.LayState.Size.Need)
 		sumPref = sumPref.Add(ni.LayState.Size.Pref)
 		maxNeed = maxNeed.Max(ni.LayState.Size.Need)
 		maxPref = maxPref.Max(ni.LayState.Size.Pref)
 
-		if Layout2DTrace {
-			fmt.Printf("Size:   %v Child: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, ni.LayState.Size.Need.Dim(LaySummedDim(ly.Lay)), ni.LayState.Size.Pref.Dim(LaySummedDim(ly.Lay)))
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Size:   %v Child: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, ni.LayState.Size.Need.Dim(LaySummedDim(ly.Lay)), ni.LayState.Size.Pref.Dim(LaySummedDim(ly.Lay)))
 		}
 	}
 	return
 }
 
+// GatherSizesLay calls the gather sizes function for the type of layout
+func GatherSizesLay(ly *Layout, iter int) {
+	switch ly.Lay {
+	case LayoutHorizFlow, LayoutVertFlow:
+		GatherSizesFlow(ly, iter)
+	case LayoutGrid:
+		GatherSizesGrid(ly)
+	default:
+		GatherSizes(ly)
+	}
+}
+
 // GatherSizes is size first pass: gather the size information from the children
 func GatherSizes(ly *Layout) {
 	sz := len(ly.Kids)
@@ -95,6 +195,14 @@ func GatherSizes(ly *Layout) {
 	}
 
 	sumPref, sumNeed, maxPref, maxNeed := GatherSizesSumMax(ly)
+	if ly.Lay == LayoutBorder { // not summed along either dim: use max
+		maxNeed = BorderSizes(ly, func(nii Node2D, ni *WidgetBase) mat32.Vec2 {
+			return ni.LayState.Size.Need
+		})
+		maxPref = BorderSizes(ly, func(nii Node2D, ni *WidgetBase) mat32.Vec2 {
+			return ni.LayState.Size.Pref
+		})
+	}
 
 	prefSizing := false
 	mvp := ly.ViewportSafe()
@@ -113,8 +221,8 @@ func GatherSizes(ly *Layout) {
 				ly.LayState.Size.Pref.SetMaxDim(d, maxPref.Dim(d))
 			}
 		} else { // use target size from style
-			if Layout2DTrace {
-				fmt.Printf("Size:   %v pref nonzero, setting as need: %v\n", ly.Path(), pref)
+			if ly.LayoutTraceOn() {
+				Layout2DTracef("Size:   %v pref nonzero, setting as need: %v\n", ly.Path(), pref)
 			}
 			ly.LayState.Size.Need.SetDim(d, pref)
 		}
@@ -125,8 +233,8 @@ func GatherSizes(ly *Layout) {
 	ly.LayState.Size.Pref.SetAddScalar(2.0 * spc)
 
 	elspc := float32(0.0)
-	if sz >= 2 {
-		elspc = float32(sz-1) * ly.Spacing.Dots
+	if nsh := NumShownKids(ly); nsh >= 2 {
+		elspc = float32(nsh-1) * ly.Spacing.Dots
 	}
 	if LaySumDim(ly.Lay, mat32.X) {
 		ly.LayState.Size.Need.X += elspc
@@ -136,13 +244,107 @@ func GatherSizes(ly *Layout) {
 		ly.LayState.Size.Need.Y += elspc
 		ly.LayState.Size.Pref.Y += elspc
 	}
+	if ly.IsBaselineSized(mat32.Y) {
+		top, bot := BaselineTrim(ly)
+		ly.LayState.Size.Need.Y -= top + bot
+		ly.LayState.Size.Pref.Y -= top + bot
+	}
 
 	ly.LayState.UpdateSizes() // enforce max and normal ordering, etc
-	if Layout2DTrace {
-		fmt.Printf("Size:   %v gather sizes need: %v, pref: %v, elspc: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref, elspc)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Size:   %v gather sizes need: %v, pref: %v, elspc: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref, elspc)
 	}
 }
 
+// ChildRegion returns the border layout region of given child
+func ChildRegion(ni *WidgetBase) gist.Region {
+	ni.StyMu.RLock()
+	defer ni.StyMu.RUnlock()
+	return ni.Sty.Layout.Region
+}
+
+// BorderSizes returns the total size of the children of a LayoutBorder,
+// using given function for the size of each child: north and south
+// children are stacked vertically above and below the row of west, center
+// and east children, with spacing between each of the stacked items.
+func BorderSizes(ly *Layout, size func(nii Node2D, ni *WidgetBase) mat32.Vec2) mat32.Vec2 {
+	var rs [gist.RegionN]mat32.Vec2
+	var ns [gist.RegionN]int
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		nii := c.(Node2D)
+		ni := nii.AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		rg := ChildRegion(ni)
+		if rg < 0 || rg >= gist.RegionN {
+			rg = gist.RegionCenter
+		}
+		sz := size(nii, ni)
+		r := &rs[rg]
+		switch rg {
+		case gist.RegionNorth, gist.RegionSouth:
+			r.X = mat32.Max(r.X, sz.X)
+			r.Y += sz.Y
+			ns[rg]++
+		case gist.RegionEast, gist.RegionWest:
+			r.X += sz.X
+			r.Y = mat32.Max(r.Y, sz.Y)
+			ns[rg]++
+		default:
+			r.SetMax(sz)
+			ns[rg] = 1 // center children overlap
+		}
+	}
+	n, s, e, w, c := rs[gist.RegionNorth], rs[gist.RegionSouth], rs[gist.RegionEast], rs[gist.RegionWest], rs[gist.RegionCenter]
+	nrow := ns[gist.RegionWest] + ns[gist.RegionCenter] + ns[gist.RegionEast]
+	ncol := ns[gist.RegionNorth] + ns[gist.RegionSouth]
+	if nrow > 0 {
+		ncol++ // the row of west, center and east
+	}
+	elspc := ly.Spacing.Dots
+	var tot mat32.Vec2
+	tot.X = mat32.Max(mat32.Max(n.X, s.X), w.X+c.X+e.X+float32(ints.MaxInt(nrow-1, 0))*elspc)
+	tot.Y = n.Y + s.Y + mat32.Max(mat32.Max(w.Y, e.Y), c.Y) + float32(ints.MaxInt(ncol-1, 0))*elspc
+	return tot
+}
+
+// BaselineTrim returns the amount of vertical space above the first baseline
+// of the first child (top) and below the last baseline of the last child
+// (bot), for children that implement Baseliner -- used for BaselineSize:
+// the layout is sized without them, and the children are positioned so the
+// first baseline is at the top of the content.
+func BaselineTrim(ly *Layout) (top, bot float32) {
+	var first, last Node2D
+	for _, c := range ly.VisualKids() {
+		if ni, _ := KiToNode2D(c); ni != nil && ni.AsWidget() != nil {
+			if first == nil {
+				first = ni
+			}
+			last = ni
+		}
+	}
+	if first == nil {
+		return
+	}
+	if bl, ok := first.(Baseliner); ok {
+		top = bl.BaselineOffset()
+	}
+	if bl, ok := last.(Baseliner); ok {
+		bot = last.AsWidget().LayState.Size.Need.Y - bl.BaselineOffset()
+	}
+	return
+}
+
+// IsBaselineSized returns true if the layout is sized by the baselines of
+// its children along given dimension -- see BaselineSize
+func (ly *Layout) IsBaselineSized(dim mat32.Dims) bool {
+	return ly.BaselineSize && ly.Lay == LayoutVert && dim == mat32.Y
+}
+
 // ChildrenUpdateSizes calls UpdateSizes on all children -- layout must at least call this
 func (ly *Layout) ChildrenUpdateSizes() {
 	for _, c := range ly.Kids {
@@ -171,8 +373,8 @@ func GatherSizesFlow(ly *Layout, iter int) {
 		ly.LayState.Size.Pref = prv
 		ly.LayState.Alloc.Size = prv
 		ly.LayState.UpdateSizes() // enforce max and normal ordering, etc
-		if Layout2DTrace {
-			fmt.Printf("Size:   %v iter 1 fix size: %v\n", ly.Path(), prv)
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Size:   %v iter 1 fix size: %v\n", ly.Path(), prv)
 		}
 		return
 	}
@@ -194,8 +396,8 @@ func GatherSizesFlow(ly *Layout, iter int) {
 		pref = 200 // final backstop
 	}
 
-	if Layout2DTrace {
-		fmt.Printf("Size:   %v flow pref start: %v\n", ly.Path(), pref)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Size:   %v flow pref start: %v\n", ly.Path(), pref)
 	}
 
 	sNeed := sumNeed.Dim(sdim)
@@ -232,8 +434,8 @@ func GatherSizesFlow(ly *Layout, iter int) {
 	ly.LayState.Size.Pref.SetAddScalar(2.0 * spc)
 
 	elspc := float32(0.0)
-	if sz >= 2 {
-		elspc = float32(sz-1) * ly.Spacing.Dots
+	if nsh := NumShownKids(ly); nsh >= 2 {
+		elspc = float32(nsh-1) * ly.Spacing.Dots
 	}
 	if LaySumDim(ly.Lay, mat32.X) {
 		ly.LayState.Size.Need.X += elspc
@@ -245,12 +447,282 @@ func GatherSizesFlow(ly *Layout, iter int) {
 	}
 
 	ly.LayState.UpdateSizes() // enforce max and normal ordering, etc
-	if Layout2DTrace {
-		fmt.Printf("Size:   %v gather sizes need: %v, pref: %v, elspc: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref, elspc)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Size:   %v gather sizes need: %v, pref: %v, elspc: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref, elspc)
+	}
+}
+
+// ResetGridData returns grid data of given length, reusing the existing
+// backing array if it has sufficient capacity, with all sizes reset to 0.
+func ResetGridData(gd []GridData, n int) []GridData {
+	if cap(gd) >= n {
+		gd = gd[:n]
+	} else {
+		gd = make([]GridData, n)
+	}
+	for i := range gd {
+		g := &gd[i]
+		g.SizeNeed = 0
+		g.SizePref = 0
+		g.SizeMax = 0
+		g.Stretch = 0
 	}
+	return gd
 }
 
-// todo: grid does not process spans at all yet -- assumes = 1
+// GridAutoSize sets the size of the implicit tracks in given grid data,
+// i.e., those beyond the first nexp explicit tracks, to given auto size
+// (grid-auto-rows, grid-auto-cols), if it is > 0 -- otherwise they keep the
+// size of their content.
+func GridAutoSize(gds []GridData, nexp int, sz float32) {
+	if sz <= 0 {
+		return
+	}
+	for i := ints.MaxInt(nexp, 0); i < len(gds); i++ {
+		gd := &gds[i]
+		gd.SizeNeed = sz
+		gd.SizePref = sz
+		gd.SizeMax = sz
+	}
+}
+
+// GridTrackSize applies the size ranges of given GridTracks to the
+// corresponding tracks of a grid, after they have been sized to their
+// content: a min size is the need of the track, a fixed max size caps its
+// pref, and an fr max size makes it stretchy from its min, with the fr
+// value as its weight.
+func GridTrackSize(gds []GridData, tracks []GridTrack, uc *units.Context) {
+	for i := range tracks {
+		if i >= len(gds) {
+			break
+		}
+		tr := &tracks[i]
+		tr.Min.ToDots(uc)
+		tr.Max.ToDots(uc)
+		gd := &gds[i]
+		if tr.Min.Dots > 0 {
+			gd.SizeNeed = tr.Min.Dots
+		}
+		switch {
+		case tr.Max.Un == units.Fr && tr.Max.Val > 0:
+			gd.SizePref = gd.SizeNeed
+			gd.SizeMax = -1
+			gd.Stretch = tr.Max.Val
+		case tr.Max.Dots > 0:
+			gd.SizePref = mat32.Min(gd.SizePref, tr.Max.Dots)
+			gd.SizeMax = tr.Max.Dots
+		}
+		gd.SizePref = mat32.Max(gd.SizePref, gd.SizeNeed)
+	}
+}
+
+// GridAutoFit sizes all of the given tracks to given min size, stretching
+// equally to fill the available space, as for the columns of an AutoFitMin
+// grid -- content wider than the min size is not taken into account.
+func GridAutoFit(gds []GridData, min float32) {
+	for i := range gds {
+		gd := &gds[i]
+		gd.SizeNeed = min
+		gd.SizePref = min
+		gd.SizeMax = -1
+	}
+}
+
+// GridSpan returns the effective number of tracks covered by a cell starting
+// at idx with given span, out of n tracks total: a span of 0 counts as 1,
+// and the span is clipped to the end of the grid.
+func GridSpan(span, idx, n int) int {
+	if span < 1 {
+		span = 1
+	}
+	if idx+span > n {
+		span = ints.MaxInt(n-idx, 1)
+	}
+	return span
+}
+
+// GridLine returns the index of the track starting at given 1-based grid
+// line, out of n tracks (n+1 lines), with negative lines counting back from
+// the last line (-1), which starts the track after the last one (index n).
+func GridLine(line, n int) int {
+	if line < 0 {
+		return ints.MaxInt(n+1+line, 0)
+	}
+	return line - 1
+}
+
+// GridLinePlace returns the index and span of a cell placed between given
+// start and end grid lines (see GridLine), out of n tracks, as for the
+// grid-column-start and -end style properties: a start or end of 0 is
+// unset, leaving the given auto-placed idx or span, respectively.
+func GridLinePlace(start, end, idx, span, n int) (int, int) {
+	if start != 0 {
+		idx = ints.MinInt(GridLine(start, n), ints.MaxInt(n-1, 0))
+	}
+	if end != 0 {
+		ed := GridLine(end, n)
+		if ed < idx { // lines in the wrong order still cover the tracks between them
+			idx, ed = ed, idx
+		}
+		span = ints.MaxInt(ed-idx, 1)
+	}
+	return idx, span
+}
+
+// GridSpanAlloc returns the allocated size of a cell starting at track idx
+// and spanning given number of tracks, including the spacing gaps between
+// the covered tracks.
+func (ly *Layout) GridSpanAlloc(rowcol RowCol, idx, span int) float32 {
+	gds := ly.GridData[rowcol]
+	span = GridSpan(span, idx, len(gds))
+	var sz float32
+	for i := idx; i < idx+span && i < len(gds); i++ {
+		sz += gds[i].AllocSize
+	}
+	return sz + float32(span-1)*ly.Spacing.Dots
+}
+
+// GridBaseline returns the offset of the first text baseline from the top
+// of given child of a grid layout in given row, if the child is vertically
+// aligned to the baseline (AlignBaseline) and implements Baseliner -- ok is
+// false otherwise.
+func (ly *Layout) GridBaseline(ni *WidgetBase, row int) (off float32, ok bool) {
+	ni.StyMu.RLock()
+	al := ly.GridChildAlignDim(ni, mat32.Y, row)
+	ni.StyMu.RUnlock()
+	if al != gist.AlignBaseline {
+		return 0, false
+	}
+	bl, ok := ni.This().(Baseliner)
+	if !ok {
+		return 0, false
+	}
+	return bl.BaselineOffset(), true
+}
+
+// GridPlace returns the row and column of a grid layout at which to place
+// a child with given layout style, starting from the given auto-placement
+// row and column.  A grid-area naming one of the GridAreas places the child
+// at the area, and sets the row and col spans of the style to cover it.
+// Likewise, grid-column-start and -end lines are resolved against the
+// current GridSize into the col and col span of the style, and the
+// grid-row-start line into the row.
+func (ly *Layout) GridPlace(lst *gist.Layout, row, col int) (int, int) {
+	if lst.GridArea != "" {
+		if ar, ok := ly.GridAreas[lst.GridArea]; ok {
+			lst.RowSpan, lst.ColSpan = ar.Dy(), ar.Dx()
+			return ar.Min.Y, ar.Min.X
+		}
+	}
+	if lst.Col > 0 {
+		col = lst.Col
+	}
+	if lst.Row > 0 {
+		row = lst.Row
+	}
+	if lst.GridColStart != 0 || lst.GridColEnd != 0 {
+		col, lst.ColSpan = GridLinePlace(lst.GridColStart, lst.GridColEnd, col, lst.ColSpan, ly.GridSize.X)
+	}
+	if lst.GridRowStart != 0 {
+		row, _ = GridLinePlace(lst.GridRowStart, 0, row, lst.RowSpan, ly.GridSize.Y)
+	}
+	return row, col
+}
+
+// GridPlaceKids returns the placement of each of the children of a grid
+// layout with given numbers of rows and cols, indexed as the Kids (with
+// zero spans for non-widgets and collapsed children, which take no cell).
+// Children with a definite cell, from a grid-area or both a row (or
+// grid-row-start) and col (or grid-column-start), are placed
+// first, and the others then follow in order from an auto-placement cursor
+// that skips over cells occupied by earlier children, including all those
+// covered by spans -- children with only a row or col take the other from
+// the cursor.  If there is no free cell, the cursor wraps around.
+func (ly *Layout) GridPlaceKids(rows, cols int) []GridCell {
+	cells := make([]GridCell, len(ly.Kids))
+	if rows <= 0 || cols <= 0 {
+		return cells
+	}
+	lsts := make([]gist.Layout, len(ly.Kids))
+	placed := make([]bool, len(ly.Kids))
+	occ := make([]bool, rows*cols)
+	place := func(i int, row, col int) {
+		lst := &lsts[i]
+		gc := GridCell{Row: row, Col: col, RowSpan: GridSpan(lst.RowSpan, row, rows), ColSpan: GridSpan(lst.ColSpan, col, cols)}
+		cells[i] = gc
+		placed[i] = true
+		for r := row; r < row+gc.RowSpan && r < rows; r++ {
+			for c := col; c < col+gc.ColSpan && c < cols; c++ {
+				occ[r*cols+c] = true
+			}
+		}
+	}
+	free := func(row, col, span int) bool {
+		for c := col; c < col+span && c < cols; c++ {
+			if occ[row*cols+c] {
+				return false
+			}
+		}
+		return true
+	}
+	skip := make([]bool, len(ly.Kids)) // not placed: non-widgets and collapsed
+	for i, c := range ly.Kids {
+		if c == nil {
+			skip[i] = true
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			skip[i] = true
+			continue
+		}
+		ni.StyMu.RLock()
+		lsts[i] = ni.Sty.Layout
+		ni.StyMu.RUnlock()
+		lst := &lsts[i]
+		_, area := ly.GridAreas[lst.GridArea]
+		if area || ((lst.Row > 0 || lst.GridRowStart != 0) && (lst.Col > 0 || lst.GridColStart != 0)) {
+			row, col := ly.GridPlace(lst, 0, 0)
+			place(i, row, col)
+		}
+	}
+	row, col := 0, 0
+	for i := range ly.Kids {
+		if skip[i] || placed[i] {
+			continue
+		}
+		lst := &lsts[i]
+		if lst.Row == 0 && lst.Col == 0 && lst.GridRowStart == 0 && lst.GridColStart == 0 && lst.GridColEnd == 0 {
+			for n := 0; n < rows*cols; n++ { // advance past occupied cells
+				if free(row, col, GridSpan(lst.ColSpan, col, cols)) {
+					break
+				}
+				col++
+				if col >= cols {
+					col = 0
+					row = (row + 1) % rows
+				}
+			}
+		} else {
+			row, col = ly.GridPlace(lst, row, col)
+		}
+		if row >= rows || col >= cols { // out of range: not in the grid
+			continue
+		}
+		place(i, row, col)
+		col += cells[i].ColSpan
+		if col >= cols {
+			col = 0
+			row++
+			if row >= rows { // wrap-around.. no other good option
+				row = 0
+			}
+		}
+	}
+	return cells
+}
+
+// todo: grid does not process spans in sizing yet -- assumes = 1
 
 // GatherSizesGrid is size first pass: gather the size information from the
 // children, grid version
@@ -259,32 +731,97 @@ func GatherSizesGrid(ly *Layout) {
 		return
 	}
 
+	prefSizing := false
+	mvp := ly.ViewportSafe()
+	if mvp != nil && mvp.HasFlag(int(VpFlagPrefSizing)) {
+		prefSizing = ly.Sty.Layout.Overflow == gist.OverflowScroll // special case
+	}
+
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ni.LayState.UpdateSizes()
+		if ni.IsCollapsed() {
+			ni.LayState.Size = gist.SizePrefs{}
+		}
+	}
+
+	ly.GridAreasUpdate()
+	if ly.GridCacheOk(prefSizing) {
+		ly.LayState.Size = ly.GridCache.Size
+		ly.GridSize = ly.GridCache.GridSize
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Size:   %v gather sizes grid cached need: %v, pref: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref)
+		}
+		return
+	}
+
 	cols := ly.Sty.Layout.Columns
-	rows := 0
+	if ly.AutoFitMin.Dots > 0 && ly.AutoFitCols > 0 {
+		cols = ly.AutoFitCols
+	}
+	tsz := GridAreasSize(ly.GridTemplateAreas) // template areas define the explicit grid
+	rows := ints.MaxInt(ly.Sty.Layout.Rows, tsz.Y)
 
-	sz := len(ly.Kids)
+	sz := 0                 // number of children taking a cell
+	var lines []gist.Layout // children placed by grid lines, resolved once cols is known
+	ncells := 0             // number of cells needed along a row if all were in one row
+	spanned := 0            // extra cells covered by spans, beyond one per child
+	maxcol := tsz.X         // max column from explicit placements
 	// collect overall size
 	for _, c := range ly.Kids {
 		if c == nil {
 			continue
 		}
 		ni := c.(Node2D).AsWidget()
-		if ni == nil {
+		if ni == nil || ni.IsCollapsed() {
 			continue
 		}
+		sz++
 		ni.StyMu.RLock()
 		lst := ni.Sty.Layout
 		ni.StyMu.RUnlock()
-		if lst.Col > 0 {
-			cols = ints.MaxInt(cols, lst.Col+lst.ColSpan)
+		if ar, ok := ly.GridAreas[lst.GridArea]; ok {
+			ncells += ar.Dx()
+			spanned += ar.Dx()*ar.Dy() - 1
+			continue
 		}
 		if lst.Row > 0 {
-			rows = ints.MaxInt(rows, lst.Row+lst.RowSpan)
+			rows = ints.MaxInt(rows, lst.Row+ints.MaxInt(lst.RowSpan, 1))
+		}
+		if lst.GridRowStart > 0 {
+			rows = ints.MaxInt(rows, lst.GridRowStart-1+ints.MaxInt(lst.RowSpan, 1))
+		}
+		if lst.Col > 0 {
+			maxcol = ints.MaxInt(maxcol, lst.Col+ints.MaxInt(lst.ColSpan, 1))
 		}
+		if lst.GridColStart != 0 || lst.GridColEnd != 0 {
+			lines = append(lines, lst)
+			ncells++
+			continue
+		}
+		ncells += ints.MaxInt(lst.ColSpan, 1)
+		spanned += ints.MaxInt(lst.ColSpan, 1)*ints.MaxInt(lst.RowSpan, 1) - 1
 	}
 
+	// Columns is a max: don't leave empty trailing columns for small content
+	if cols > ncells {
+		cols = ncells
+	}
+	cols = ints.MaxInt(cols, maxcol)
 	if cols == 0 {
-		cols = int(mat32.Sqrt(float32(sz))) // whatever -- not well defined
+		cols = ly.ClampColumns(int(mat32.Sqrt(float32(sz)))) // whatever -- not well defined
+	}
+	cols = ints.MaxInt(cols, 1) // e.g., all children collapsed
+	sz += spanned
+	for i := range lines { // make room for the extra cells spanned between lines
+		_, span := GridLinePlace(lines[i].GridColStart, lines[i].GridColEnd, 0, lines[i].ColSpan, cols)
+		sz += GridSpan(span, 0, cols) - 1
 	}
 	if rows == 0 {
 		rows = sz / cols
@@ -296,27 +833,13 @@ func GatherSizesGrid(ly *Layout) {
 	ly.GridSize.X = cols
 	ly.GridSize.Y = rows
 
-	if len(ly.GridData[Row]) != rows {
-		ly.GridData[Row] = make([]GridData, rows)
-	}
-	if len(ly.GridData[Col]) != cols {
-		ly.GridData[Col] = make([]GridData, cols)
-	}
-
-	for i := range ly.GridData[Row] {
-		gd := &ly.GridData[Row][i]
-		gd.SizeNeed = 0
-		gd.SizePref = 0
-	}
-	for i := range ly.GridData[Col] {
-		gd := &ly.GridData[Col][i]
-		gd.SizeNeed = 0
-		gd.SizePref = 0
-	}
+	ly.GridData[Row] = ResetGridData(ly.GridData[Row], rows)
+	ly.GridData[Col] = ResetGridData(ly.GridData[Col], cols)
 
-	col := 0
-	row := 0
-	for _, c := range ly.Kids {
+	var asc, desc []float32    // per row, above and below the baseline
+	usedRows, usedCols := 0, 0 // extent of the cells occupied by children
+	cells := ly.GridPlaceKids(rows, cols)
+	for ci, c := range ly.Kids {
 		if c == nil {
 			continue
 		}
@@ -324,15 +847,20 @@ func GatherSizesGrid(ly *Layout) {
 		if ni == nil {
 			continue
 		}
-		ni.LayState.UpdateSizes()
-		ni.StyMu.RLock()
-		lst := ni.Sty.Layout
-		ni.StyMu.RUnlock()
-		if lst.Col > 0 {
-			col = lst.Col
+		gc := cells[ci]
+		if gc.RowSpan == 0 { // not placed
+			continue
 		}
-		if lst.Row > 0 {
-			row = lst.Row
+		row, col := gc.Row, gc.Col
+		usedRows = ints.MaxInt(usedRows, row+gc.RowSpan)
+		usedCols = ints.MaxInt(usedCols, col+gc.ColSpan)
+		if off, ok := ly.GridBaseline(ni, row); ok && gc.RowSpan == 1 {
+			if asc == nil {
+				asc = make([]float32, rows)
+				desc = make([]float32, rows)
+			}
+			mat32.SetMax(&asc[row], off)
+			mat32.SetMax(&desc[row], ni.LayState.Size.Need.Y-off)
 		}
 		// r   0   1   col X = max(ea in col) (Y = not used)
 		//   +--+---+
@@ -350,38 +878,58 @@ func GatherSizesGrid(ly *Layout) {
 		mat32.SetMax(&(cgd.SizeNeed), ni.LayState.Size.Need.X)
 		mat32.SetMax(&(cgd.SizePref), ni.LayState.Size.Pref.X)
 
-		// for max: any -1 stretch dominates, else accumulate any max
-		if rgd.SizeMax >= 0 {
-			if ni.LayState.Size.Max.Y < 0 { // stretch
-				rgd.SizeMax = -1
-			} else {
-				mat32.SetMax(&(rgd.SizeMax), ni.LayState.Size.Max.Y)
+		// for max: any -1 stretch dominates, and is propagated to all the
+		// tracks covered by a spanning element -- else accumulate any max
+		if ni.LayState.Size.Max.Y < 0 { // stretch
+			for i := row; i < row+gc.RowSpan; i++ {
+				ly.GridData[Row][i].SizeMax = -1
 			}
+		} else if rgd.SizeMax >= 0 {
+			mat32.SetMax(&(rgd.SizeMax), ni.LayState.Size.Max.Y)
 		}
-		if cgd.SizeMax >= 0 {
-			if ni.LayState.Size.Max.Y < 0 { // stretch
-				cgd.SizeMax = -1
-			} else {
-				mat32.SetMax(&(cgd.SizeMax), ni.LayState.Size.Max.X)
+		if ni.LayState.Size.Max.X < 0 { // stretch
+			for i := col; i < col+gc.ColSpan; i++ {
+				ly.GridData[Col][i].SizeMax = -1
 			}
+		} else if cgd.SizeMax >= 0 {
+			mat32.SetMax(&(cgd.SizeMax), ni.LayState.Size.Max.X)
 		}
+	}
 
-		col++
-		if col >= cols { // todo: really only works if NO items specify row,col or ALL do..
-			col = 0
-			row++
-			if row >= rows { // wrap-around.. no other good option
-				row = 0
-			}
+	// trim trailing rows and columns that no child reached, e.g., from a
+	// rows count larger than needed, so they take no space -- except those
+	// of the grid-template-areas, which define the explicit grid
+	usedRows = ints.MaxInt(usedRows, tsz.Y)
+	usedCols = ints.MaxInt(usedCols, tsz.X)
+	if usedRows > 0 && usedRows < rows {
+		rows = usedRows
+		ly.GridData[Row] = ly.GridData[Row][:rows]
+		if asc != nil {
+			asc = asc[:rows]
+			desc = desc[:rows]
 		}
 	}
+	if usedCols > 0 && usedCols < cols {
+		cols = usedCols
+		ly.GridData[Col] = ly.GridData[Col][:cols]
+	}
+	ly.GridSize.X = cols
+	ly.GridSize.Y = rows
 
-	prefSizing := false
-	mvp := ly.ViewportSafe()
-	if mvp != nil && mvp.HasFlag(int(VpFlagPrefSizing)) {
-		prefSizing = ly.Sty.Layout.Overflow == gist.OverflowScroll // special case
+	for i := range asc { // baseline-aligned cells extend the row
+		rgd := &(ly.GridData[Row][i])
+		mat32.SetMax(&(rgd.SizeNeed), asc[i]+desc[i])
+		mat32.SetMax(&(rgd.SizePref), asc[i]+desc[i])
 	}
 
+	GridAutoSize(ly.GridData[Row], ly.Sty.Layout.Rows, ly.Sty.Layout.GridAutoRows.Dots)
+	GridAutoSize(ly.GridData[Col], ly.Sty.Layout.Columns, ly.Sty.Layout.GridAutoCols.Dots)
+	if ly.AutoFitMin.Dots > 0 {
+		GridAutoFit(ly.GridData[Col], ly.AutoFitMin.Dots)
+	}
+	GridTrackSize(ly.GridData[Row], ly.GridTemplateRows, &ly.Sty.UnContext)
+	GridTrackSize(ly.GridData[Col], ly.GridTemplateCols, &ly.Sty.UnContext)
+
 	// if there aren't existing prefs, we need to compute size
 	if prefSizing || ly.LayState.Size.Pref.X == 0 || ly.LayState.Size.Pref.Y == 0 {
 		sbw := ly.Sty.Layout.ScrollBarWidth.Dots
@@ -437,13 +985,119 @@ func GatherSizesGrid(ly *Layout) {
 	ly.LayState.Size.Pref.X += float32(cols-1) * ly.Spacing.Dots
 	ly.LayState.Size.Need.Y += float32(rows-1) * ly.Spacing.Dots
 	ly.LayState.Size.Pref.Y += float32(rows-1) * ly.Spacing.Dots
+	if mw := ly.AutoFitMin.Dots; mw > 0 { // can reflow down to one column
+		ly.LayState.Size.Need.X = mat32.Min(ly.LayState.Size.Need.X, mw+2.0*spc)
+	}
 
 	ly.LayState.UpdateSizes() // enforce max and normal ordering, etc
-	if Layout2DTrace {
-		fmt.Printf("Size:   %v gather sizes grid need: %v, pref: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref)
+	ly.GridCache.Size = ly.LayState.Size
+	ly.GridCache.GridSize = ly.GridSize
+	ly.GridCache.Valid = true
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Size:   %v gather sizes grid need: %v, pref: %v\n", ly.Path(), ly.LayState.Size.Need, ly.LayState.Size.Pref)
 	}
 }
 
+// GridCacheOk returns true if the GridCache results are valid for the
+// current children sizes and placements and the grid styles of the layout,
+// so that GatherSizesGrid can be skipped.  Otherwise it records the current
+// inputs in the cache, which remains invalid until the results are saved.
+func (ly *Layout) GridCacheOk(prefSizing bool) bool {
+	gc := &ly.GridCache
+	st := &ly.Sty.Layout
+	ok := gc.Valid && len(gc.Kids) == len(ly.Kids) && gc.Columns == st.Columns && gc.Rows == st.Rows &&
+		gc.AutoFit == ly.AutoFitCols && gc.AutoFitMin == ly.AutoFitMin.Dots &&
+		gc.MinCols == ly.MinColumns && gc.MaxCols == ly.MaxColumns &&
+		StringsEqual(gc.Areas, ly.GridTemplateAreas) &&
+		GridTracksEqual(gc.ColTracks, ly.GridTemplateCols) && GridTracksEqual(gc.RowTracks, ly.GridTemplateRows) &&
+		gc.AlignItems == ly.GridAlignItems && GridItemAlignsEqual(gc.RowAligns, ly.GridRowAligns) &&
+		gc.AutoRows == st.GridAutoRows.Dots && gc.AutoCols == st.GridAutoCols.Dots &&
+		gc.ScrollBar == st.ScrollBarWidth.Dots && gc.Spacing == ly.Spacing.Dots &&
+		gc.BoxSpc == ly.BoxSpace() && gc.PrefSizing == prefSizing &&
+		gc.SizeIn == ly.LayState.Size && gc.AllocIn == ly.LayState.Alloc.Size
+	gc.Columns, gc.Rows = st.Columns, st.Rows
+	gc.AutoFit, gc.AutoFitMin = ly.AutoFitCols, ly.AutoFitMin.Dots
+	gc.MinCols, gc.MaxCols = ly.MinColumns, ly.MaxColumns
+	gc.Areas = append(gc.Areas[:0], ly.GridTemplateAreas...)
+	gc.ColTracks = append(gc.ColTracks[:0], ly.GridTemplateCols...)
+	gc.RowTracks = append(gc.RowTracks[:0], ly.GridTemplateRows...)
+	gc.AlignItems = ly.GridAlignItems
+	gc.RowAligns = append(gc.RowAligns[:0], ly.GridRowAligns...)
+	gc.AutoRows, gc.AutoCols = st.GridAutoRows.Dots, st.GridAutoCols.Dots
+	gc.ScrollBar, gc.Spacing, gc.BoxSpc = st.ScrollBarWidth.Dots, ly.Spacing.Dots, ly.BoxSpace()
+	gc.PrefSizing = prefSizing
+	gc.SizeIn, gc.AllocIn = ly.LayState.Size, ly.LayState.Alloc.Size
+	if cap(gc.Kids) >= len(ly.Kids) {
+		gc.Kids = gc.Kids[:len(ly.Kids)]
+	} else {
+		gc.Kids = make([]GridSizeKid, len(ly.Kids))
+	}
+	for i, c := range ly.Kids {
+		kd := GridSizeKid{Kid: c}
+		if c != nil {
+			if ni := c.(Node2D).AsWidget(); ni != nil {
+				ni.StyMu.RLock()
+				lst := &ni.Sty.Layout
+				kd.Row, kd.Col, kd.RowSpan, kd.ColSpan = lst.Row, lst.Col, lst.RowSpan, lst.ColSpan
+				kd.RowStart, kd.ColStart, kd.ColEnd = lst.GridRowStart, lst.GridColStart, lst.GridColEnd
+				kd.Area = lst.GridArea
+				kd.AlignV = lst.AlignV
+				ni.StyMu.RUnlock()
+				kd.Size = ni.LayState.Size
+				if bl, ok := ni.This().(Baseliner); ok {
+					kd.BaseOff = bl.BaselineOffset()
+				}
+			}
+		}
+		if gc.Kids[i] != kd {
+			ok = false
+			gc.Kids[i] = kd
+		}
+	}
+	gc.Valid = ok
+	return ok
+}
+
+// StringsEqual returns true if the two lists of strings are the same
+func StringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GridTracksEqual returns true if the two lists of grid tracks are the same
+func GridTracksEqual(a, b []GridTrack) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GridItemAlignsEqual returns true if the two lists of grid item
+// alignments are the same
+func GridItemAlignsEqual(a, b []GridItemAligns) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // LayAllocFromParent: if we are not a child of a layout, then get allocation
 // from a parent obj that has a layout size
 func LayAllocFromParent(ly *Layout) {
@@ -470,8 +1124,8 @@ func LayAllocFromParent(ly *Layout) {
 			}
 			if !pg.LayState.Alloc.Size.IsNil() {
 				ly.LayState.Alloc.Size = pg.LayState.Alloc.Size
-				if Layout2DTrace {
-					fmt.Printf("Layout: %v got parent alloc: %v from %v\n", ly.Path(), ly.LayState.Alloc.Size, pg.Path())
+				if ly.LayoutTraceOn() {
+					Layout2DTracef("Layout: %v got parent alloc: %v from %v\n", ly.Path(), ly.LayState.Alloc.Size, pg.Path())
 				}
 				return ki.Break
 			}
@@ -521,8 +1175,12 @@ func LayoutSharedDimImpl(ly *Layout, avail, need, pref, max, spc float32, al gis
 			pos += extra
 		} else if al == gist.AlignJustify { // treat justify as stretch
 			size += extra
+			if max > 0 { // but not beyond a max set by style
+				size = mat32.Min(size, max)
+			}
 		}
 	}
+	size = mat32.Max(size, need) // need is a hard floor
 
 	// if Layout2DTrace {
 	// 	fmt.Printf("ly %v avail: %v targ: %v, extra %v, strMax: %v, strNeed: %v, pos: %v size: %v spc: %v\n", ly.Nm, avail, targ, extra, stretchMax, stretchNeed, pos, size, spc)
@@ -531,6 +1189,183 @@ func LayoutSharedDimImpl(ly *Layout, avail, need, pref, max, spc float32, al gis
 	return
 }
 
+// StretchShare returns the share of given extra space for a stretchy element
+// with given stretch weight, out of the total weight of all nstretch
+// stretchy elements, according to the StretchMode of the layout.
+func (ly *Layout) StretchShare(extra, wt, tot float32, nstretch int) float32 {
+	if ly.StretchMode == StretchEqual {
+		return extra / float32(nstretch)
+	}
+	return extra * (wt / tot)
+}
+
+// FlexDistribute distributes given amount among items in proportion to
+// their weights, without giving any item more than its limit (if >= 0):
+// the excess over the limit is redistributed among the other items.
+// Returns the amount given to each item, which may total less than amt
+// if all of the items with weight reach their limits.
+func FlexDistribute(amt float32, wts, lims []float32) []float32 {
+	n := len(wts)
+	shares := make([]float32, n)
+	done := make([]bool, n)
+	for amt > 0 {
+		tot := float32(0)
+		for i, wt := range wts {
+			if !done[i] && wt > 0 {
+				tot += wt
+			}
+		}
+		if tot <= 0 {
+			break
+		}
+		capped := false
+		for i, wt := range wts { // cap all that exceed their limit first
+			if done[i] || wt <= 0 || lims[i] < 0 {
+				continue
+			}
+			if shares[i]+amt*(wt/tot) > lims[i] {
+				amt -= lims[i] - shares[i]
+				shares[i] = lims[i]
+				done[i] = true
+				capped = true
+			}
+		}
+		if capped { // redistribute the rest among the others
+			continue
+		}
+		for i, wt := range wts {
+			if !done[i] && wt > 0 {
+				shares[i] += amt * (wt / tot)
+			}
+		}
+		break
+	}
+	return shares
+}
+
+// FlexGrowShares returns the shares of given extra space along given
+// dimension for the given children of the layout, as they are laid out by
+// LayoutAlongDim: the stretchy and growing ones (and those that can stretch
+// beyond their Need size, if need is true) share the extra in proportion to
+// their StretchWeight, according to the StretchMode, with growing ones
+// limited by their Max size.  Those with a Shrink factor do not stretch
+// beyond their Need size: if the children only fit in their Need sizes,
+// they have already shrunk to them, see FlexShrinkCuts.
+func (ly *Layout) FlexGrowShares(kids ki.Slice, dim mat32.Dims, extra float32, need bool) []float32 {
+	wts := make([]float32, len(kids))
+	lims := make([]float32, len(kids))
+	for i, c := range kids {
+		lims[i] = -1
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ld := &ni.LayState
+		if !ld.CanGrow(dim) && !(need && ld.CanStretchNeed(dim)) {
+			continue
+		}
+		wts[i] = ld.StretchWeight(dim)
+		if ly.StretchMode == StretchEqual {
+			wts[i] = 1
+		}
+		base := ld.Size.Pref.Dim(dim)
+		if need {
+			base = ld.Size.Need.Dim(dim)
+		}
+		if !ld.Size.HasMaxStretch(dim) {
+			lims[i] = ld.GrowLimit(dim, base)
+		}
+	}
+	return FlexDistribute(extra, wts, lims)
+}
+
+// FlexShrinkCuts returns the amounts by which the given children of the
+// layout shrink below their Pref sizes along given dimension, when their
+// total Pref size exceeds the given available space, as they are laid out
+// by LayoutAlongDim: those with a Shrink factor give up space in proportion
+// to it times their Pref size, down to their Need size.  Returns nil if the
+// children fit, or no child has a Shrink factor.
+func (ly *Layout) FlexShrinkCuts(kids ki.Slice, dim mat32.Dims, avail float32) []float32 {
+	wts := make([]float32, len(kids))
+	lims := make([]float32, len(kids))
+	any := false
+	sumPref := float32(0)
+	for i, c := range kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		ld := &ni.LayState
+		sumPref += ld.Size.Pref.Dim(dim)
+		if ld.Shrink <= 0 {
+			continue
+		}
+		wts[i] = ld.Shrink * ld.Size.Pref.Dim(dim)
+		lims[i] = mat32.Max(ld.Size.Pref.Dim(dim)-ld.Size.Need.Dim(dim), 0)
+		any = true
+	}
+	deficit := sumPref - avail
+	if !any || deficit < 0.1 {
+		return nil
+	}
+	return FlexDistribute(deficit, wts, lims)
+}
+
+// FlexCutsFit returns true if the given shrink cuts from FlexShrinkCuts
+// bring the given total Pref size of the children within the available
+// space -- otherwise the children are all at their Need sizes.
+func FlexCutsFit(cuts []float32, sumPref, avail float32) bool {
+	cut := float32(0)
+	for _, c := range cuts {
+		cut += c
+	}
+	return sumPref-cut <= avail+0.1
+}
+
+// ChildAlignDim returns the alignment of given child along given dimension
+// -- the alignment of the layout itself if InheritAlign is set and the
+// child does not set the corresponding property itself (or in its type
+// properties).  Must be called with the child StyMu read-locked.
+func (ly *Layout) ChildAlignDim(ni *WidgetBase, dim mat32.Dims) gist.Align {
+	if ly.InheritAlign {
+		key := "vertical-align"
+		if dim == mat32.X {
+			key = "horizontal-align"
+		}
+		if _, has := ni.PropInherit(key, ki.NoInherit, ki.TypeProps); !has {
+			return ly.Sty.Layout.AlignDim(dim)
+		}
+	}
+	return ni.Sty.Layout.AlignDim(dim)
+}
+
+// GridChildAlignDim returns the alignment of given child within its grid
+// cell in given row along given dimension, resolved in order: the alignment
+// set by the child itself (or its type properties), then the
+// GridJustifyItems (X) or the GridRowAlign of the row (Y) of the layout if
+// not unset, then ChildAlignDim (which includes InheritAlign and defaults to
+// the start horizontally).  Must be called with the child StyMu read-locked.
+func (ly *Layout) GridChildAlignDim(ni *WidgetBase, dim mat32.Dims, row int) gist.Align {
+	key := "vertical-align"
+	items := ly.GridRowAlign(row)
+	if dim == mat32.X {
+		key = "horizontal-align"
+		items = ly.GridJustifyItems
+	}
+	if items != GridItemsUnset {
+		if _, has := ni.PropInherit(key, ki.NoInherit, ki.TypeProps); !has {
+			return items.Align(dim)
+		}
+	}
+	return ly.ChildAlignDim(ni, dim)
+}
+
 // LayoutSharedDim lays out items along a shared dimension, where all elements
 // share the same space, e.g., Horiz for a Vert layout, and vice-versa.
 func LayoutSharedDim(ly *Layout, dim mat32.Dims) {
@@ -548,7 +1383,7 @@ func LayoutSharedDim(ly *Layout, dim mat32.Dims) {
 			continue
 		}
 		ni.StyMu.RLock()
-		al := ni.Sty.Layout.AlignDim(dim)
+		al := ly.ChildAlignDim(ni, dim)
 		ni.StyMu.RUnlock()
 		pref := ni.LayState.Size.Pref.Dim(dim)
 		need := ni.LayState.Size.Need.Dim(dim)
@@ -557,6 +1392,151 @@ func LayoutSharedDim(ly *Layout, dim mat32.Dims) {
 		ni.LayState.Alloc.Size.SetDim(dim, size)
 		ni.LayState.Alloc.PosRel.SetDim(dim, pos)
 	}
+	if ly.CenterWhenFits && dim == mat32.Y {
+		LayoutCenterShared(ly, dim)
+	}
+}
+
+// LayoutCenterShared centers the children of the layout as a group along
+// given dimension (shared or summed), keeping their positions relative to
+// each other, if they fit within the layout -- for CenterWhenFits.
+func LayoutCenterShared(ly *Layout, dim mat32.Dims) {
+	spc := ly.BoxSpace()
+	avail := ly.LayState.Alloc.Size.Dim(dim) - 2.0*spc
+	var kids []*WidgetBase
+	var st, ed float32
+	for i, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		if ly.Lay == LayoutStacked && ly.StackTopOnly && i != ly.StackTop {
+			continue
+		}
+		pos := ni.LayState.Alloc.PosRel.Dim(dim)
+		end := pos + ni.LayState.Alloc.Size.Dim(dim)
+		if len(kids) == 0 || pos < st {
+			st = pos
+		}
+		if len(kids) == 0 || end > ed {
+			ed = end
+		}
+		kids = append(kids, ni)
+	}
+	extra := avail - (ed - st)
+	if len(kids) == 0 || extra <= 0 {
+		return
+	}
+	del := spc + 0.5*extra - st
+	for _, ni := range kids {
+		ni.LayState.Alloc.PosRel.SetAddDim(dim, del)
+	}
+}
+
+// LayoutStackedFill lays out all children of a stacked layout to the full
+// content size of the layout, positioned at the origin -- used for FillStack.
+func LayoutStackedFill(ly *Layout) {
+	spc := ly.BoxSpace()
+	avail := ly.LayState.Alloc.Size.SubScalar(2.0 * spc)
+	for i, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		if ly.StackTopOnly && i != ly.StackTop {
+			continue
+		}
+		ni.LayState.Alloc.Size = avail
+		ni.LayState.Alloc.PosRel.Set(spc, spc)
+	}
+}
+
+// LayoutBorderRegions lays out the children of a LayoutBorder in their
+// regions: north and south children span the full width at their preferred
+// height, then west and east children span the remaining height at their
+// preferred width, and center children get all the remaining space, so
+// they must scroll if that is less than they need.  Multiple children in
+// the same edge region are stacked toward the center, in tree order.
+func LayoutBorderRegions(ly *Layout) {
+	spc := ly.BoxSpace()
+	elspc := ly.Spacing.Dots
+	avail := ly.LayState.Alloc.Size.SubScalar(2.0 * spc)
+	min := mat32.NewVec2(spc, spc)            // upper-left of remaining space
+	max := min.Add(avail.Max(mat32.Vec2Zero)) // lower-right of remaining space
+	// spacing only goes between the items actually present, as in
+	// BorderSizes: the stacked north, south and middle row vertically, and
+	// the west, center and east items of the middle row horizontally
+	var ns [gist.RegionN]int
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		if ni := c.(Node2D).AsWidget(); ni != nil && !ni.IsCollapsed() {
+			rg := ChildRegion(ni)
+			if rg < 0 || rg >= gist.RegionN {
+				rg = gist.RegionCenter
+			}
+			ns[rg]++
+		}
+	}
+	hleft := ns[gist.RegionWest] + ns[gist.RegionEast] + ints.MinInt(ns[gist.RegionCenter], 1)
+	vleft := ns[gist.RegionNorth] + ns[gist.RegionSouth] + ints.MinInt(hleft, 1)
+	gap := func(left *int) float32 { // spacing after placing an item, if any are left
+		*left--
+		if *left > 0 {
+			return elspc
+		}
+		return 0
+	}
+	for pass := 0; pass < 3; pass++ {
+		for _, c := range ly.Kids {
+			if c == nil {
+				continue
+			}
+			ni := c.(Node2D).AsWidget()
+			if ni == nil || ni.IsCollapsed() {
+				continue
+			}
+			rg := ChildRegion(ni)
+			pref := ni.LayState.Size.Pref
+			rem := max.Sub(min).Max(mat32.Vec2Zero)
+			alc := &ni.LayState.Alloc
+			switch {
+			case pass == 0 && rg == gist.RegionNorth:
+				h := mat32.Min(pref.Y, rem.Y)
+				alc.Size.Set(rem.X, h)
+				alc.PosRel = min
+				min.Y += h + gap(&vleft)
+			case pass == 0 && rg == gist.RegionSouth:
+				h := mat32.Min(pref.Y, rem.Y)
+				alc.Size.Set(rem.X, h)
+				alc.PosRel.Set(min.X, max.Y-h)
+				max.Y -= h + gap(&vleft)
+			case pass == 1 && rg == gist.RegionWest:
+				w := mat32.Min(pref.X, rem.X)
+				alc.Size.Set(w, rem.Y)
+				alc.PosRel = min
+				min.X += w + gap(&hleft)
+			case pass == 1 && rg == gist.RegionEast:
+				w := mat32.Min(pref.X, rem.X)
+				alc.Size.Set(w, rem.Y)
+				alc.PosRel.Set(max.X-w, min.Y)
+				max.X -= w + gap(&hleft)
+			case pass == 2 && (rg == gist.RegionCenter || rg < 0 || rg >= gist.RegionN):
+				alc.Size = rem
+				alc.PosRel = min
+			}
+		}
+	}
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v border center: %v - %v\n", ly.Path(), min, max)
+	}
 }
 
 // LayoutAlongDim lays out all children along given dim -- only affects that dim --
@@ -567,18 +1547,30 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 		return
 	}
 
-	elspc := float32(sz-1) * ly.Spacing.Dots
+	elspc := float32(ints.MaxInt(NumShownKids(ly)-1, 0)) * ly.Spacing.Dots
 	al := ly.Sty.Layout.AlignDim(dim)
 	spc := ly.BoxSpace()
 	exspc := 2.0*spc + elspc
 	avail := ly.LayState.Alloc.Size.Dim(dim) - exspc
 	pref := ly.LayState.Size.Pref.Dim(dim) - exspc
 	need := ly.LayState.Size.Need.Dim(dim) - exspc
+	// our own pref can be fixed by style, beyond what the children
+	// actually take, which would then leave no extra for them
+	sumPref, sumNeed, _, _ := GatherSizesSumMax(ly)
+	pref = mat32.Min(pref, sumPref.Dim(dim))
+	need = mat32.Min(need, sumNeed.Dim(dim))
 
+	kids := ly.VisualKids()
 	targ := pref
 	usePref := true
 	extra := avail - targ
-	if extra < -0.1 { // not fitting in pref, go with need
+	cuts := ly.FlexShrinkCuts(kids, dim, avail)
+	if cuts != nil && !FlexCutsFit(cuts, sumPref.Dim(dim), avail) {
+		cuts = nil // shrinking is not enough: the rest comes from need
+	}
+	if cuts != nil { // children not fitting, and some can shrink
+		extra = 0
+	} else if avail-sumPref.Dim(dim) < -0.1 { // not fitting in pref, go with need
 		usePref = false
 		targ = need
 		extra = avail - targ
@@ -599,13 +1591,13 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 			if ni == nil {
 				continue
 			}
-			if ni.LayState.Size.HasMaxStretch(dim) { // negative = stretch
+			if ni.LayState.CanGrow(dim) { // negative = stretch, or grow factor
 				nstretch++
-				stretchTot += ni.LayState.Size.Pref.Dim(dim)
+				stretchTot += ni.LayState.StretchWeight(dim)
 			}
 		}
 		if nstretch > 0 {
-			stretchMax = true // only stretch those marked as infinitely stretchy
+			stretchMax = true // only stretch those marked as stretchy or growing
 		}
 	} else if extra > 0.0 { // extra relative to Need
 		for _, c := range ly.Kids {
@@ -616,9 +1608,9 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 			if ni == nil {
 				continue
 			}
-			if ni.LayState.Size.HasMaxStretch(dim) || ni.LayState.Size.CanStretchNeed(dim) {
+			if ni.LayState.CanGrow(dim) || ni.LayState.CanStretchNeed(dim) {
 				nstretch++
-				stretchTot += ni.LayState.Size.Pref.Dim(dim)
+				stretchTot += ni.LayState.StretchWeight(dim)
 			}
 		}
 		if nstretch > 0 {
@@ -626,6 +1618,11 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 		}
 	}
 
+	var shares []float32
+	if stretchMax || stretchNeed {
+		shares = ly.FlexGrowShares(kids, dim, extra, stretchNeed)
+	}
+
 	extraSpace := float32(0.0)
 	if sz > 1 && extra > 0.0 && al == gist.AlignJustify && !stretchNeed && !stretchMax {
 		addSpace = true
@@ -635,17 +1632,27 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 
 	// now arrange everyone
 	pos := spc
+	if ly.IsBaselineSized(dim) { // first baseline at the top of the content
+		top, _ := BaselineTrim(ly)
+		pos -= top
+	}
 
 	// todo: need a direction setting too
-	if gist.IsAlignEnd(al) && !stretchNeed && !stretchMax {
-		pos += extra
+	if !stretchNeed && !stretchMax {
+		if gist.IsAlignEnd(al) {
+			pos += extra
+		} else if al == gist.AlignCenter || (al == gist.AlignMiddle && dim == mat32.X) {
+			// center the group of children -- AlignMiddle is the default
+			// vertical-align, so only an explicit center centers vertically
+			pos += 0.5 * extra
+		}
 	}
 
-	if Layout2DTrace {
-		fmt.Printf("Layout: %v Along dim %v, avail: %v elspc: %v need: %v pref: %v targ: %v, extra %v, strMax: %v, strNeed: %v, nstr %v, strTot %v\n", ly.Path(), dim, avail, elspc, need, pref, targ, extra, stretchMax, stretchNeed, nstretch, stretchTot)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v Along dim %v, avail: %v elspc: %v need: %v pref: %v targ: %v, extra %v, strMax: %v, strNeed: %v, nstr %v, strTot %v\n", ly.Path(), dim, avail, elspc, need, pref, targ, extra, stretchMax, stretchNeed, nstretch, stretchTot)
 	}
 
-	for i, c := range ly.Kids {
+	for i, c := range kids {
 		if c == nil {
 			continue
 		}
@@ -657,27 +1664,91 @@ func LayoutAlongDim(ly *Layout, dim mat32.Dims) {
 		if usePref {
 			size = ni.LayState.Size.Pref.Dim(dim)
 		}
-		if stretchMax { // negative = stretch
-			if ni.LayState.Size.HasMaxStretch(dim) { // in proportion to pref
-				size += extra * (ni.LayState.Size.Pref.Dim(dim) / stretchTot)
-			}
-		} else if stretchNeed {
-			if ni.LayState.Size.HasMaxStretch(dim) || ni.LayState.Size.CanStretchNeed(dim) {
-				size += extra * (ni.LayState.Size.Pref.Dim(dim) / stretchTot)
-			}
+		if cuts != nil {
+			size -= cuts[i]
+		}
+		if stretchMax || stretchNeed { // in proportion to weight (pref), within limits
+			size += shares[i]
 		} else if addSpace { // implies align justify
 			if i > 0 {
 				pos += extraSpace
 			}
 		}
+		// need (min) is a hard floor, even if it overflows the layout --
+		// the overflow is then handled by scrolling
+		size = mat32.Max(size, ni.LayState.Size.Need.Dim(dim))
 
+		if ni.IsCollapsed() { // takes no space, including spacing
+			ni.LayState.Alloc.Size.SetDim(dim, 0)
+			ni.LayState.Alloc.PosRel.SetDim(dim, pos)
+			continue
+		}
 		ni.LayState.Alloc.Size.SetDim(dim, size)
 		ni.LayState.Alloc.PosRel.SetDim(dim, pos)
-		if Layout2DTrace {
-			fmt.Printf("Layout: %v Child: %v, pos: %v, size: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, pos, size, ni.LayState.Size.Need.Dim(dim), ni.LayState.Size.Pref.Dim(dim))
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Layout: %v Child: %v, pos: %v, size: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, pos, size, ni.LayState.Size.Need.Dim(dim), ni.LayState.Size.Pref.Dim(dim))
 		}
 		pos += size + ly.Spacing.Dots
 	}
+	if dim == mat32.X && ly.IsRTL() {
+		// mirror, within the content if it overflows, so it still scrolls
+		end := mat32.Max(ly.LayState.Alloc.Size.X, pos-ly.Spacing.Dots+spc)
+		for _, c := range ly.Kids {
+			if c == nil {
+				continue
+			}
+			if ni := c.(Node2D).AsWidget(); ni != nil {
+				alc := &ni.LayState.Alloc
+				alc.PosRel.X = end - alc.PosRel.X - alc.Size.X
+			}
+		}
+	}
+	if ly.CenterWhenFits && dim == mat32.Y {
+		// extra above is relative to our own pref, which can be fixed to
+		// the alloc, so center on the actual extent -- none once overflowing
+		LayoutCenterShared(ly, dim)
+	}
+}
+
+// IsRTL returns true if the TextDir of the layout is right-to-left
+func (ly *Layout) IsRTL() bool {
+	return ly.TextDir == gist.RTL || ly.TextDir == gist.RLTB || ly.TextDir == gist.RL
+}
+
+// LayoutFlowJustify distributes the extra space at the end of each line of
+// a flow layout between its items along given dimension, so each full line
+// fills the layout -- the last line is left at the start, as in text.
+func LayoutFlowJustify(ly *Layout, dim mat32.Dims) {
+	end := ly.LayState.Alloc.Size.Dim(dim) - ly.BoxSpace()
+	ci := 0
+	for li, bi := range ly.FlowBreaks {
+		if li == len(ly.FlowBreaks)-1 {
+			break
+		}
+		var kids []*WidgetBase
+		for i := ci; i < bi; i++ {
+			c := ly.Kids[i]
+			if c == nil {
+				continue
+			}
+			if ni := c.(Node2D).AsWidget(); ni != nil && !ni.IsCollapsed() {
+				kids = append(kids, ni)
+			}
+		}
+		ci = bi
+		if len(kids) < 2 {
+			continue
+		}
+		last := kids[len(kids)-1]
+		extra := end - (last.LayState.Alloc.PosRel.Dim(dim) + last.LayState.Alloc.Size.Dim(dim))
+		if extra <= 0 {
+			continue
+		}
+		gap := extra / float32(len(kids)-1)
+		for i, ni := range kids {
+			ni.LayState.Alloc.PosRel.SetDim(dim, ni.LayState.Alloc.PosRel.Dim(dim)+float32(i)*gap)
+		}
+	}
 }
 
 // LayoutFlow manages the flow layout along given dimension
@@ -689,11 +1760,11 @@ func LayoutFlow(ly *Layout, dim mat32.Dims, iter int) bool {
 		return false
 	}
 
-	elspc := float32(sz-1) * ly.Spacing.Dots
+	elspc := float32(ints.MaxInt(NumShownKids(ly)-1, 0)) * ly.Spacing.Dots
 	spc := ly.BoxSpace()
 	exspc := 2.0*spc + elspc
 
-	avail := ly.LayState.Alloc.Size.Dim(dim) - exspc
+	end := ly.LayState.Alloc.Size.Dim(dim) - spc // pos already includes spacing
 	odim := mat32.OtherDim(dim)
 
 	pos := spc
@@ -705,19 +1776,27 @@ func LayoutFlow(ly *Layout, dim mat32.Dims, iter int) bool {
 		if ni == nil {
 			continue
 		}
+		if ni.IsCollapsed() { // takes no space, including spacing
+			ni.LayState.Alloc.Size.SetDim(dim, 0)
+			ni.LayState.Alloc.PosRel.SetDim(dim, pos)
+			continue
+		}
 		size := ni.LayState.Size.Need.Dim(dim)
-		if pos+size > avail {
+		if pos+size > end && pos > spc {
 			ly.FlowBreaks = append(ly.FlowBreaks, i)
 			pos = spc
 		}
 		ni.LayState.Alloc.Size.SetDim(dim, size)
 		ni.LayState.Alloc.PosRel.SetDim(dim, pos)
-		if Layout2DTrace {
-			fmt.Printf("Layout: %v Child: %v, pos: %v, size: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, pos, size, ni.LayState.Size.Need.Dim(dim), ni.LayState.Size.Pref.Dim(dim))
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Layout: %v Child: %v, pos: %v, size: %v, need: %v, pref: %v\n", ly.Path(), ni.Nm, pos, size, ni.LayState.Size.Need.Dim(dim), ni.LayState.Size.Pref.Dim(dim))
 		}
 		pos += size + ly.Spacing.Dots
 	}
 	ly.FlowBreaks = append(ly.FlowBreaks, len(ly.Kids))
+	if ly.Sty.Layout.AlignDim(dim) == gist.AlignJustify {
+		LayoutFlowJustify(ly, dim)
+	}
 
 	nrows := len(ly.FlowBreaks)
 	oavail := ly.LayState.Alloc.Size.Dim(odim) - exspc
@@ -736,8 +1815,13 @@ func LayoutFlow(ly *Layout, dim mat32.Dims, iter int) bool {
 			if ni == nil {
 				continue
 			}
+			if ni.IsCollapsed() {
+				ni.LayState.Alloc.Size.SetDim(odim, 0)
+				ni.LayState.Alloc.PosRel.SetDim(odim, rpos)
+				continue
+			}
 			ni.StyMu.RLock()
-			al := ni.Sty.Layout.AlignDim(odim)
+			al := ly.ChildAlignDim(ni, odim)
 			ni.StyMu.RUnlock()
 			pref := ni.LayState.Size.Pref.Dim(odim)
 			need := ni.LayState.Size.Need.Dim(odim)
@@ -754,8 +1838,8 @@ func LayoutFlow(ly *Layout, dim mat32.Dims, iter int) bool {
 	}
 	ly.LayState.Size.Need = nsz
 	ly.LayState.Size.Pref = nsz
-	if Layout2DTrace {
-		fmt.Printf("Layout: %v Flow final size: %v\n", ly.Path(), nsz)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v Flow final size: %v\n", ly.Path(), nsz)
 	}
 	// if nrows == 1 {
 	// 	return false
@@ -779,6 +1863,10 @@ func LayoutGridDim(ly *Layout, rowcol RowCol, dim mat32.Dims) {
 	avail := ly.LayState.Alloc.Size.Dim(dim) - exspc
 	pref := ly.LayState.Size.Pref.Dim(dim) - exspc
 	need := ly.LayState.Size.Need.Dim(dim) - exspc
+	if rowcol == Col && ly.AutoFitMin.Dots > 0 { // auto-fit tracks always stretch to fill
+		pref = float32(sz) * ly.AutoFitMin.Dots
+		need = pref
+	}
 
 	targ := pref
 	usePref := true
@@ -796,20 +1884,20 @@ func LayoutGridDim(ly *Layout, rowcol RowCol, dim mat32.Dims) {
 	stretchMax := false         // only stretch Max = neg
 	addSpace := false           // apply extra toward spacing -- for justify
 	if usePref && extra > 0.0 { // have some stretch extra
-		for _, gd := range gds {
-			if gd.SizeMax < 0 { // stretch
+		for i := range gds {
+			if gds[i].SizeMax < 0 { // stretch
 				nstretch++
-				stretchTot += gd.SizePref
+				stretchTot += gds[i].StretchWeight()
 			}
 		}
 		if nstretch > 0 {
 			stretchMax = true // only stretch those marked as infinitely stretchy
 		}
 	} else if extra > 0.0 { // extra relative to Need
-		for _, gd := range gds {
-			if gd.SizeMax < 0 || gd.SizePref > gd.SizeNeed {
+		for i := range gds {
+			if gds[i].SizeMax < 0 || gds[i].SizePref > gds[i].SizeNeed {
 				nstretch++
-				stretchTot += gd.SizePref
+				stretchTot += gds[i].StretchWeight()
 			}
 		}
 		if nstretch > 0 {
@@ -828,12 +1916,18 @@ func LayoutGridDim(ly *Layout, rowcol RowCol, dim mat32.Dims) {
 	pos := spc
 
 	// todo: need a direction setting too
-	if gist.IsAlignEnd(al) && !stretchNeed && !stretchMax {
-		pos += extra
+	if !stretchNeed && !stretchMax {
+		if gist.IsAlignEnd(al) {
+			pos += extra
+		} else if al == gist.AlignCenter || (al == gist.AlignMiddle && dim == mat32.X) {
+			// center the group of children -- AlignMiddle is the default
+			// vertical-align, so only an explicit center centers vertically
+			pos += 0.5 * extra
+		}
 	}
 
-	if Layout2DTrace {
-		fmt.Printf("Layout Grid Dim: %v All on dim %v, avail: %v need: %v pref: %v targ: %v, extra %v, strMax: %v, strNeed: %v, nstr %v, strTot %v\n", ly.Path(), dim, avail, need, pref, targ, extra, stretchMax, stretchNeed, nstretch, stretchTot)
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout Grid Dim: %v All on dim %v, avail: %v need: %v pref: %v targ: %v, extra %v, strMax: %v, strNeed: %v, nstr %v, strTot %v\n", ly.Path(), dim, avail, need, pref, targ, extra, stretchMax, stretchNeed, nstretch, stretchTot)
 	}
 
 	for i := range gds {
@@ -843,28 +1937,105 @@ func LayoutGridDim(ly *Layout, rowcol RowCol, dim mat32.Dims) {
 			size = gd.SizePref
 		}
 		if stretchMax { // negative = stretch
-			if gd.SizeMax < 0 { // in proportion to pref
-				size += extra * (gd.SizePref / stretchTot)
+			if gd.SizeMax < 0 { // in proportion to weight (pref)
+				size += ly.StretchShare(extra, gd.StretchWeight(), stretchTot, nstretch)
 			}
 		} else if stretchNeed {
 			if gd.SizeMax < 0 || gd.SizePref > gd.SizeNeed {
-				size += extra * (gd.SizePref / stretchTot)
+				size += ly.StretchShare(extra, gd.StretchWeight(), stretchTot, nstretch)
 			}
 		} else if addSpace { // implies align justify
 			if i > 0 {
 				pos += extraSpace
 			}
 		}
+		size = mat32.Max(size, gd.SizeNeed) // need is a hard floor
 
 		gd.AllocSize = size
 		gd.AllocPosRel = pos
-		if Layout2DTrace {
-			fmt.Printf("Grid %v pos: %v, size: %v\n", rowcol, pos, size)
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Grid %v pos: %v, size: %v\n", rowcol, pos, size)
 		}
 		pos += size + ly.Spacing.Dots
 	}
 }
 
+// AutoFitColumns computes the number of columns of a grid layout with an
+// AutoFitMin width, from the allocated width: as many columns of at least
+// that width as fit, with spacing, and at least 1.  If that changes the
+// number of columns, the grid sizes are re-gathered, and it returns true.
+func (ly *Layout) AutoFitColumns() bool {
+	mw := ly.AutoFitMin.Dots
+	if mw <= 0 {
+		return false
+	}
+	avail := ly.LayState.Alloc.Size.X - 2.0*ly.BoxSpace()
+	elspc := ly.Spacing.Dots
+	cols := ly.ClampColumns(ints.MaxInt(int((avail+elspc)/(mw+elspc)), 1))
+	if cols == ly.AutoFitCols {
+		return false
+	}
+	if ly.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v auto-fit columns: %v from avail: %v\n", ly.Path(), cols, avail)
+	}
+	ly.AutoFitCols = cols
+	alc := ly.LayState.Alloc
+	ly.InitLayout2D() // re-gather from the style sizes
+	GatherSizesGrid(ly)
+	ly.LayState.Alloc = alc
+	return true
+}
+
+// ClampColumns clamps given automatically-computed number of grid columns
+// to the MinColumns, MaxColumns range, where set.
+func (ly *Layout) ClampColumns(cols int) int {
+	if ly.MaxColumns > 0 {
+		cols = ints.MinInt(cols, ly.MaxColumns)
+	}
+	if ly.MinColumns > 0 {
+		cols = ints.MaxInt(cols, ly.MinColumns)
+	}
+	return cols
+}
+
+// ComputePreferredColumns returns the max number of columns that the
+// children would be wrapped into to fit within given available width,
+// where each column is as wide as the widest preferred width of the children
+// in it, plus spacing between columns -- at least 1 if there are any
+// children.  The children must have already been sized in Size2D.  Does
+// not change anything, so it can be used to preview a responsive layout.
+func (ly *Layout) ComputePreferredColumns(avail float32) int {
+	var prefs []float32
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil || ni.IsCollapsed() {
+			continue
+		}
+		prefs = append(prefs, ni.LayState.Size.Pref.X)
+	}
+	if len(prefs) == 0 {
+		return 0
+	}
+	best := 1
+	for cols := 2; cols <= len(prefs); cols++ {
+		wd := float32(cols-1) * ly.Spacing.Dots
+		for ci := 0; ci < cols; ci++ {
+			cw := float32(0)
+			for i := ci; i < len(prefs); i += cols {
+				cw = mat32.Max(cw, prefs[i])
+			}
+			wd += cw
+		}
+		if wd <= avail {
+			best = cols
+		}
+	}
+	return best
+}
+
 // LayoutGridLay manages overall grid layout of children
 func LayoutGridLay(ly *Layout) {
 	sz := len(ly.Kids)
@@ -875,16 +2046,18 @@ func LayoutGridLay(ly *Layout) {
 	LayoutGridDim(ly, Row, mat32.Y)
 	LayoutGridDim(ly, Col, mat32.X)
 
-	col := 0
-	row := 0
-	cols := ly.GridSize.X
-	rows := ly.GridSize.Y
-
-	if cols*rows != ly.NumChildren() {
+	if ly.GridSize.X*ly.GridSize.Y != ly.NumChildren() {
 		GatherSizesGrid(ly)
 	}
+	cells := ly.GridPlaceKids(ly.GridSize.Y, ly.GridSize.X)
 
-	for _, c := range ly.Kids {
+	type baseKid struct {
+		ni       *WidgetBase
+		row      int
+		off, top float32
+	}
+	var bkids []baseKid
+	for ci, c := range ly.Kids {
 		if c == nil {
 			continue
 		}
@@ -892,22 +2065,19 @@ func LayoutGridLay(ly *Layout) {
 		if ni == nil {
 			continue
 		}
-
-		ni.StyMu.RLock()
-		lst := ni.Sty.Layout
-		ni.StyMu.RUnlock()
-		if lst.Col > 0 {
-			col = lst.Col
-		}
-		if lst.Row > 0 {
-			row = lst.Row
+		gc := cells[ci]
+		if gc.RowSpan == 0 { // not placed
+			continue
 		}
+		row, col := gc.Row, gc.Col
 
 		{ // col, X dim
 			dim := mat32.X
 			gd := ly.GridData[Col][col]
-			avail := gd.AllocSize
-			al := lst.AlignDim(dim)
+			avail := ly.GridSpanAlloc(Col, col, gc.ColSpan)
+			ni.StyMu.RLock()
+			al := ly.GridChildAlignDim(ni, dim, row)
+			ni.StyMu.RUnlock()
 			pref := ni.LayState.Size.Pref.Dim(dim)
 			need := ni.LayState.Size.Need.Dim(dim)
 			max := ni.LayState.Size.Max.Dim(dim)
@@ -919,29 +2089,92 @@ func LayoutGridLay(ly *Layout) {
 		{ // row, Y dim
 			dim := mat32.Y
 			gd := ly.GridData[Row][row]
-			avail := gd.AllocSize
-			al := lst.AlignDim(dim)
+			avail := ly.GridSpanAlloc(Row, row, gc.RowSpan)
+			ni.StyMu.RLock()
+			al := ly.GridChildAlignDim(ni, dim, row)
+			ni.StyMu.RUnlock()
 			pref := ni.LayState.Size.Pref.Dim(dim)
 			need := ni.LayState.Size.Need.Dim(dim)
 			max := ni.LayState.Size.Max.Dim(dim)
 			pos, size := LayoutSharedDimImpl(ly, avail, need, pref, max, 0, al)
 			ni.LayState.Alloc.Size.SetDim(dim, size)
 			ni.LayState.Alloc.PosRel.SetDim(dim, pos+gd.AllocPosRel)
+			if off, ok := ly.GridBaseline(ni, row); ok && gc.RowSpan == 1 {
+				bkids = append(bkids, baseKid{ni, row, off, gd.AllocPosRel})
+			}
 		}
 
-		if Layout2DTrace {
-			fmt.Printf("Layout: %v grid col: %v row: %v pos: %v size: %v\n", ly.Path(), col, row, ni.LayState.Alloc.PosRel, ni.LayState.Alloc.Size)
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Layout: %v grid col: %v row: %v pos: %v size: %v\n", ly.Path(), col, row, ni.LayState.Alloc.PosRel, ni.LayState.Alloc.Size)
 		}
+	}
 
-		col++
-		if col >= cols { // todo: really only works if NO items specify row,col or ALL do..
-			col = 0
-			row++
-			if row >= rows { // wrap-around.. no other good option
-				row = 0
-			}
+	// baseline-aligned cells: align the baselines within each row
+	if len(bkids) == 0 {
+		return
+	}
+	base := make([]float32, ly.GridSize.Y)
+	for _, bk := range bkids {
+		mat32.SetMax(&base[bk.row], bk.off)
+	}
+	for _, bk := range bkids {
+		bk.ni.LayState.Alloc.PosRel.Y = bk.top + base[bk.row] - bk.off
+	}
+}
+
+// LayoutAspect applies the aspect-ratio style of the children to their
+// allocated sizes: the height is computed from the allocated width, and if
+// that would exceed the max-height or the height of the cell allocated to the
+// child, the height is clamped and the width reduced to keep the ratio
+// within both bounds -- the children of a LayoutVert are not constrained by
+// their cell, as it can make room for them.  The height is saved in
+// LayoutState.AspectH for sizing, and it returns true if it changed, so the
+// sizes gathered before are out of date.
+func LayoutAspect(ly *Layout) bool {
+	changed := false
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ni.StyMu.RLock()
+		ar := ni.Sty.Layout.AspectRatio
+		ni.StyMu.RUnlock()
+		if ar <= 0 {
+			continue
+		}
+		w := ni.LayState.Alloc.Size.X
+		if mx := ni.LayState.Size.Max.X; mx > 0 {
+			w = mat32.Min(w, mx)
+		}
+		if w <= 0 {
+			continue
+		}
+		h := w / ar
+		mxh := float32(0) // a vertical layout makes room for the height
+		if ly.Lay != LayoutVert {
+			mxh = ni.LayState.Alloc.Size.Y // cell allocated by the layout
+		}
+		if mx := ni.LayState.Size.Max.Y; mx > 0 && (mxh <= 0 || mx < mxh) {
+			mxh = mx
+		}
+		if mxh > 0 && h > mxh {
+			h = mxh
+			w = h * ar
+		}
+		ni.LayState.Alloc.Size.Set(w, h)
+		if mat32.Abs(h-ni.LayState.AspectH) > LayoutEqualTol {
+			ni.LayState.AspectH = h
+			changed = true
+		}
+		if ly.LayoutTraceOn() {
+			Layout2DTracef("Layout: %v aspect ratio: %v child: %v size: %v\n", ly.Path(), ar, ni.Nm, ni.LayState.Alloc.Size)
 		}
 	}
+	return changed
 }
 
 // FinalizeLayout is final pass through children to finalize the layout,
@@ -972,4 +2205,8 @@ func (ly *Layout) FinalizeLayout() {
 		ly.ChildSize.SetMax(ni.LayState.Alloc.PosRel.Add(ni.LayState.Alloc.Size))
 		ni.LayState.Alloc.SizeOrig = ni.LayState.Alloc.Size
 	}
+	if ly.IsBaselineSized(mat32.Y) { // content ends at the last baseline
+		_, bot := BaselineTrim(ly)
+		ly.ChildSize.Y -= bot
+	}
 }
@@ -0,0 +1,3274 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// testIconMgr is a minimal IconMgr with no icons, standing in for the
+// svg.IconMgr which cannot be imported here
+type testIconMgr struct{}
+
+func (im *testIconMgr) IsValid(iconName string) bool { return false }
+func (im *testIconMgr) SetIcon(ic *Icon, iconName string) error {
+	return errors.New("no icons in test")
+}
+func (im *testIconMgr) IconByName(name string) (ki.Ki, error) {
+	return nil, errors.New("no icons in test")
+}
+func (im *testIconMgr) IconList(alphaSort bool) []IconName { return nil }
+
+func init() {
+	if TheIconMgr == nil {
+		TheIconMgr = &testIconMgr{}
+	}
+}
+
+// testViewport returns a new viewport of given size for use as the
+// root of a test scenegraph
+func testViewport(width, height int) *Viewport2D {
+	vp := NewViewport2D(width, height)
+	vp.InitName(vp, "vp")
+	return vp
+}
+
+// testWindowViewport returns a viewport in a bare window, which is not
+// opened, but provides the EventMgr for testing focus
+func testWindowViewport(width, height int) *Viewport2D {
+	win := &Window{}
+	win.InitName(win, "win")
+	win.EventMgr.Master = win
+	vp := testViewport(width, height)
+	vp.Win = win
+	win.Viewport = vp
+	return vp
+}
+
+// testOSWin is an OS window that is always visible, standing in for an
+// opened window in testRender2D -- all other methods are unimplemented
+type testOSWin struct {
+	oswin.Window
+}
+
+func (w *testOSWin) IsVisible() bool { return true }
+
+// testRender2D renders the children of given viewport, which must already
+// be laid out, directly into its Pixels -- the viewport is not visible
+// without an opened window, so FullRender2DTree does not render anything.
+func testRender2D(vp *Viewport2D) {
+	win := vp.Win
+	vp.Win = &Window{OSWin: &testOSWin{}}
+	vp.Win.InitName(vp.Win, "win")
+	vp.Win.Viewport = vp
+	for _, kid := range vp.Kids {
+		kid.(Node2D).AsNode2D().Render2DTree()
+	}
+	vp.Win = win
+}
+
+// addTestBox adds a fixed-size Space of given size (in px) to parent
+func addTestBox(par ki.Ki, name string, w, h float32) *Space {
+	sp := AddNewSpace(par, name)
+	sp.SetFixedWidth(units.NewPx(w))
+	sp.SetFixedHeight(units.NewPx(h))
+	return sp
+}
+
+// testSizeTree runs the init, style and size passes on given viewport
+func testSizeTree(vp *Viewport2D) {
+	vp.Init2DTree()
+	vp.Style2DTree()
+	vp.Size2DTree(0)
+}
+
+func TestGridColStretch(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	hs := AddNewSpace(ly, "hstretch")
+	hs.SetProp("width", units.NewPx(20))
+	hs.SetProp("max-width", -1)
+	hs.SetFixedHeight(units.NewPx(20))
+	addTestBox(ly, "fixed", 20, 20)
+	testSizeTree(vp)
+
+	if ly.GridSize.X != 2 || ly.GridSize.Y != 1 {
+		t.Fatalf("grid size: %v, expected 2 x 1", ly.GridSize)
+	}
+	if ly.GridData[Col][0].SizeMax >= 0 {
+		t.Errorf("col 0 should be stretchy, SizeMax: %v", ly.GridData[Col][0].SizeMax)
+	}
+	if ly.GridData[Col][1].SizeMax < 0 {
+		t.Errorf("col 1 should not be stretchy, SizeMax: %v", ly.GridData[Col][1].SizeMax)
+	}
+	if ly.GridData[Row][0].SizeMax < 0 {
+		t.Errorf("row 0 should not be stretchy, SizeMax: %v", ly.GridData[Row][0].SizeMax)
+	}
+}
+
+func TestGridAutoRows(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	ly.SetProp("rows", 2)
+	ly.SetProp("grid-auto-rows", units.NewPx(50))
+	for i := 0; i < 8; i++ {
+		addTestBox(ly, "box", 20, 30)
+	}
+	testSizeTree(vp)
+	rows := ly.GridData[Row]
+	if len(rows) != 4 {
+		t.Fatalf("number of rows: %v, expected 4", len(rows))
+	}
+	for i, gd := range rows {
+		exp := float32(30)
+		if i >= 2 {
+			exp = 50
+		}
+		if gd.SizePref != exp {
+			t.Errorf("row %d size: %v, expected %v", i, gd.SizePref, exp)
+		}
+	}
+	if ly.LayState.Size.Pref.Y != 160 {
+		t.Errorf("grid pref height: %v, expected 160", ly.LayState.Size.Pref.Y)
+	}
+}
+
+func TestGridTrimEmptyRows(t *testing.T) {
+	grid := func(rows int) *Layout {
+		vp := testViewport(400, 300)
+		ly := AddNewLayout(vp, "grid", LayoutGrid)
+		ly.SetProp("columns", 2)
+		ly.SetProp("rows", rows)
+		ly.SetProp("spacing", units.NewPx(10))
+		for i := 0; i < 4; i++ {
+			addTestBox(ly, "box", 20, 30)
+		}
+		testSizeTree(vp)
+		return ly
+	}
+	ly := grid(3)
+	if ly.GridSize.Y != 2 || len(ly.GridData[Row]) != 2 {
+		t.Errorf("rows: %v, data: %v, expected empty third row trimmed to 2", ly.GridSize.Y, len(ly.GridData[Row]))
+	}
+	if exp := grid(2).LayState.Size.Pref; ly.LayState.Size.Pref != exp {
+		t.Errorf("grid pref size: %v, expected same as 2 rows: %v", ly.LayState.Size.Pref, exp)
+	}
+}
+
+func TestGridAlignCenterBlock(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	ly.SetProp("horizontal-align", gist.AlignCenter)
+	ly.SetProp("vertical-align", gist.AlignCenter)
+	ly.SetStretchMax()
+	for i := 0; i < 4; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	vp.FullRender2DTree()
+
+	spc := ly.BoxSpace()
+	avail := ly.LayState.Alloc.Size.SubScalar(2 * spc)
+	if avail.X <= 40 || avail.Y <= 40 {
+		t.Fatalf("grid avail: %v, expected larger than the 40x40 tracks", avail)
+	}
+	off := mat32.NewVec2(spc+0.5*(avail.X-40), spc+0.5*(avail.Y-40))
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		exp := off.Add(mat32.NewVec2(float32(20*(i%2)), float32(20*(i/2))))
+		if ni.LayState.Alloc.PosRel != exp {
+			t.Errorf("cell %d pos: %v, expected grid block centered at: %v", i, ni.LayState.Alloc.PosRel, exp)
+		}
+	}
+}
+
+func TestGridOverflowingChildren(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	for i := 0; i < 4; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ovf := ly.OverflowingChildren(); len(ovf) != 0 {
+		t.Errorf("overflowing children: %v, expected none", ovf)
+	}
+
+	// implicit second column forced smaller than the minimum of its children
+	ly.SetProp("columns", 1)
+	ly.SetProp("grid-auto-cols", units.NewPx(10))
+	ly.Child(3).SetProp("col", 1)
+	vp.FullRender2DTree()
+	ovf := ly.OverflowingChildren()
+	if len(ovf) != 2 || ovf[0] != ly.Child(1).(Node2D) || ovf[1] != ly.Child(3).(Node2D) {
+		t.Errorf("overflowing children: %v, expected children 1 and 3 in second column", ovf)
+	}
+}
+
+func TestGridSpanStretch(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	ly.SetStretchMax()
+	hdr := AddNewSpace(ly, "header")
+	hdr.SetProp("col-span", 2)
+	hdr.SetProp("width", units.NewPx(20))
+	hdr.SetProp("height", units.NewPx(10))
+	hdr.SetStretchMaxWidth()
+	addTestBox(ly, "fixed", 30, 10)
+	flex := AddNewSpace(ly, "flex")
+	flex.SetProp("width", units.NewPx(40))
+	flex.SetProp("height", units.NewPx(10))
+	vp.FullRender2DTree()
+
+	cols := ly.GridData[Col]
+	for i, gd := range cols {
+		if gd.SizeMax >= 0 {
+			t.Errorf("col %d max: %v, expected stretch from spanning header", i, gd.SizeMax)
+		}
+		if gd.AllocSize <= gd.SizePref {
+			t.Errorf("col %d alloc: %v, expected stretched beyond pref: %v", i, gd.AllocSize, gd.SizePref)
+		}
+	}
+	exp := cols[0].AllocSize + cols[1].AllocSize
+	if sz := hdr.LayState.Alloc.Size.X; sz != exp {
+		t.Errorf("header width: %v, expected %v", sz, exp)
+	}
+}
+
+func TestGridColumnsMax(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 5)
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ly.GridSize != image.Pt(3, 1) {
+		t.Errorf("grid size: %v, expected 3 cols x 1 row", ly.GridSize)
+	}
+	if ly.LayState.Size.Pref.X != 60 {
+		t.Errorf("grid pref width: %v, expected 60", ly.LayState.Size.Pref.X)
+	}
+
+	// explicit placement beyond the child count still extends the grid
+	ly.Child(2).SetProp("col", 4)
+	vp.FullRender2DTree()
+	if ly.GridSize.X != 5 {
+		t.Errorf("grid cols: %v, expected 5 with explicit placement", ly.GridSize.X)
+	}
+}
+
+func TestGridSetColumns(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetColumns(2)
+	for i := 0; i < 6; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ly.Columns() != 2 || ly.GridSize != image.Pt(2, 3) {
+		t.Fatalf("grid columns: %v size: %v, expected 2 cols x 3 rows", ly.Columns(), ly.GridSize)
+	}
+	ly.SetColumns(3)
+	if ly.GridSize != image.Pt(3, 2) { // re-laid out by UpdateEnd
+		t.Errorf("grid size after SetColumns: %v, expected to be re-laid out as 3 x 2", ly.GridSize)
+	}
+	vp.FullRender2DTree()
+	if ly.Columns() != 3 || ly.GridSize != image.Pt(3, 2) {
+		t.Errorf("grid columns: %v size: %v, expected 3 cols x 2 rows", ly.Columns(), ly.GridSize)
+	}
+	y0 := ly.Child(0).(Node2D).AsWidget().LayState.Alloc.PosRel.Y
+	if y := ly.Child(2).(Node2D).AsWidget().LayState.Alloc.PosRel.Y; y != y0 {
+		t.Errorf("3rd item row pos: %v, expected reflowed into first row at %v", y, y0)
+	}
+}
+
+func TestFrameBgBoxStretchX(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	outer.SetStretchMax()
+	fr := AddNewFrame(outer, "frame", LayoutHoriz)
+	fr.SetStretchMaxWidth()
+	addTestBox(fr, "box", 50, 20)
+	vp.FullRender2DTree()
+
+	alloc := fr.LayState.Alloc
+	if alloc.Size.X <= fr.LayState.Size.Pref.X || alloc.Size.Y != fr.LayState.Size.Pref.Y {
+		t.Fatalf("frame alloc: %v, expected stretched in X only from pref: %v", alloc.Size, fr.LayState.Size.Pref)
+	}
+	mrg := fr.Sty.Layout.Margin.Dots
+	if mrg <= 0 {
+		t.Fatalf("frame margin: %v, expected default margin", mrg)
+	}
+	pos, sz := fr.BgBox()
+	if pos != alloc.Pos.AddScalar(mrg) {
+		t.Errorf("bg pos: %v, expected alloc pos inside margin: %v", pos, alloc.Pos.AddScalar(mrg))
+	}
+	exp := mat32.NewVec2(alloc.Size.X-2*mrg, alloc.Size.Y-2*mrg)
+	if sz != exp {
+		t.Errorf("bg size: %v, expected content box: %v", sz, exp)
+	}
+}
+
+func TestLayoutViewportUnits(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	hero := AddNewSpace(outer, "hero")
+	hero.SetProp("width", units.NewValue(25, units.Vw))
+	hero.SetProp("height", units.NewValue(50, units.Vh))
+	vp.FullRender2DTree()
+	if sz := hero.LayState.Alloc.Size; sz != mat32.NewVec2(100, 150) {
+		t.Errorf("hero size: %v, expected 25vw x 50vh = (100, 150)", sz)
+	}
+}
+
+func TestStackedFill(t *testing.T) {
+	vp := testViewport(200, 150)
+	fr := AddNewFrame(vp, "stack", LayoutStacked)
+	fr.FillStack = true
+	addTestBox(fr, "small", 20, 10)
+	addTestBox(fr, "big", 50, 30)
+	vp.FullRender2DTree()
+
+	spc := fr.BoxSpace()
+	exp := fr.LayState.Alloc.Size.SubScalar(2 * spc)
+	if exp.X <= 50 || exp.Y <= 30 {
+		t.Fatalf("stack content size: %v, expected larger than children", exp)
+	}
+	for i, k := range fr.Kids {
+		ni := k.(Node2D).AsWidget()
+		if ni.LayState.Alloc.Size != exp {
+			t.Errorf("child %d alloc size: %v, expected %v", i, ni.LayState.Alloc.Size, exp)
+		}
+		if ni.LayState.Alloc.PosRel.X != spc || ni.LayState.Alloc.PosRel.Y != spc {
+			t.Errorf("child %d pos: %v, expected origin at %v", i, ni.LayState.Alloc.PosRel, spc)
+		}
+	}
+}
+
+func TestLayoutTraceNode(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "outer", LayoutVert)
+	inner := AddNewLayout(ly, "inner", LayoutHoriz)
+	addTestBox(inner, "box1", 20, 10)
+	other := AddNewLayout(ly, "other", LayoutHoriz)
+	addTestBox(other, "box2", 20, 10)
+	inner.TraceLayout = true
+
+	var msgs []string
+	prvf := Layout2DTracef
+	Layout2DTracef = func(format string, a ...interface{}) (int, error) {
+		msg := fmt.Sprintf(format, a...)
+		msgs = append(msgs, msg)
+		return len(msg), nil
+	}
+	defer func() { Layout2DTracef = prvf }()
+
+	vp.FullRender2DTree()
+	if len(msgs) == 0 {
+		t.Fatalf("no trace messages for node with TraceLayout set")
+	}
+	for _, msg := range msgs {
+		if !strings.Contains(msg, inner.Path()) {
+			t.Errorf("trace message not from traced node: %s", msg)
+		}
+	}
+}
+
+func TestLayoutAlignCenterGroup(t *testing.T) {
+	for n := 1; n <= 2; n++ {
+		vp := testViewport(200, 100)
+		ly := AddNewLayout(vp, "row", LayoutHoriz)
+		ly.SetProp("horizontal-align", gist.AlignCenter)
+		ly.SetStretchMax()
+		for i := 0; i < n; i++ {
+			addTestBox(ly, "box", 20, 10)
+		}
+		vp.FullRender2DTree()
+
+		avail := ly.LayState.Alloc.Size.X - 2*ly.BoxSpace()
+		st := ly.BoxSpace() + 0.5*(avail-float32(n*20))
+		for i, k := range ly.Kids {
+			ni := k.(Node2D).AsWidget()
+			exp := st + float32(i*20)
+			if ni.LayState.Alloc.PosRel.X != exp {
+				t.Errorf("%d children: child %d pos: %v, expected %v", n, i, ni.LayState.Alloc.PosRel.X, exp)
+			}
+		}
+	}
+}
+
+func TestLayoutInheritAlign(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "form", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(200))
+	ly.SetProp("horizontal-align", gist.AlignCenter)
+	ly.InheritAlign = true
+	inh := addTestBox(ly, "inh", 20, 20)
+	own := addTestBox(ly, "own", 20, 20)
+	own.SetProp("horizontal-align", gist.AlignLeft)
+	vp.FullRender2DTree()
+
+	x0 := own.LayState.Alloc.PosRel.X
+	if x := inh.LayState.Alloc.PosRel.X; x != x0+90 {
+		t.Errorf("inherited align pos: %v, expected centered at %v", x, x0+90)
+	}
+
+	ly.InheritAlign = false
+	vp.FullRender2DTree()
+	if x := inh.LayState.Alloc.PosRel.X; x != x0 {
+		t.Errorf("non-inherited align pos: %v, expected default left at %v", x, x0)
+	}
+}
+
+func TestLayoutSpacing(t *testing.T) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "row", LayoutHoriz)
+	ly.SetProp("spacing", units.NewPx(8))
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 10)
+	}
+	vp.FullRender2DTree()
+
+	if ly.LayState.Size.Need.X != 76 {
+		t.Errorf("need with spacing: %v, expected 76", ly.LayState.Size.Need.X)
+	}
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		exp := float32(i * 28)
+		if ni.LayState.Alloc.PosRel.X != exp {
+			t.Errorf("child %d pos: %v, expected %v", i, ni.LayState.Alloc.PosRel.X, exp)
+		}
+	}
+
+	// justify adds extra space on top of the spacing
+	ly.SetProp("horizontal-align", gist.AlignJustify)
+	ly.SetStretchMax()
+	vp.FullRender2DTree()
+	extra := (ly.LayState.Alloc.Size.X - 76) / 2
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		exp := float32(i*28) + float32(i)*extra
+		if ni.LayState.Alloc.PosRel.X != exp {
+			t.Errorf("justify child %d pos: %v, expected %v", i, ni.LayState.Alloc.PosRel.X, exp)
+		}
+	}
+}
+
+func TestGridSpanSpacing(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	ly.SetProp("spacing", units.NewPx(10))
+	hdr := AddNewSpace(ly, "header")
+	hdr.SetProp("col-span", 2)
+	hdr.SetProp("width", units.NewPx(20))
+	hdr.SetProp("height", units.NewPx(10))
+	hdr.SetStretchMaxWidth()
+	addTestBox(ly, "left", 30, 10)
+	addTestBox(ly, "right", 40, 10)
+	vp.FullRender2DTree()
+
+	cols := ly.GridData[Col]
+	exp := cols[0].AllocSize + 10 + cols[1].AllocSize
+	if sz := hdr.LayState.Alloc.Size.X; sz != exp {
+		t.Errorf("spanning header width: %v, expected %v", sz, exp)
+	}
+	rt := ly.Child(2).(Node2D).AsWidget()
+	if rt.LayState.Alloc.PosRel.Y != 20 {
+		t.Errorf("right box row pos: %v, expected 20", rt.LayState.Alloc.PosRel.Y)
+	}
+	if rt.LayState.Alloc.PosRel.X != cols[1].AllocPosRel {
+		t.Errorf("right box col pos: %v, expected %v", rt.LayState.Alloc.PosRel.X, cols[1].AllocPosRel)
+	}
+}
+
+func TestLayoutDisplayNone(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "col", LayoutVert)
+	ly.SetProp("spacing", units.NewPx(10))
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	mid := ly.Child(1).(Node2D).AsWidget()
+	last := ly.Child(2).(Node2D).AsWidget()
+	mid.SetProp("display", "none")
+	vp.FullRender2DTree()
+
+	if !mid.IsCollapsed() {
+		t.Fatalf("display: none did not collapse")
+	}
+	if ly.LayState.Size.Need.Y != 50 {
+		t.Errorf("need: %v, expected 50 excluding collapsed child", ly.LayState.Size.Need.Y)
+	}
+	if mid.LayState.Alloc.Size.Y != 0 {
+		t.Errorf("collapsed size: %v, expected 0", mid.LayState.Alloc.Size.Y)
+	}
+	if last.LayState.Alloc.PosRel.Y != 30 {
+		t.Errorf("last pos: %v, expected 30", last.LayState.Alloc.PosRel.Y)
+	}
+	if !mid.VpBBox.Empty() || !mid.IsInvisible() {
+		t.Errorf("collapsed child should not render, bbox: %v", mid.VpBBox)
+	}
+
+	mid.SetProp("display", "block")
+	vp.FullRender2DTree()
+	if mid.IsCollapsed() {
+		t.Fatalf("display: block did not restore")
+	}
+	if last.LayState.Alloc.PosRel.Y != 60 {
+		t.Errorf("restored last pos: %v, expected 60", last.LayState.Alloc.PosRel.Y)
+	}
+	if mid.VpBBox.Empty() {
+		t.Errorf("restored child bbox is empty")
+	}
+
+	// a collapsed child takes no grid cell, and no flow space or spacing
+	vp = testViewport(400, 200)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	grid := AddNewLayout(outer, "grid", LayoutGrid)
+	grid.SetProp("columns", 2)
+	flow := AddNewLayout(outer, "flow", LayoutHorizFlow)
+	flow.SetProp("spacing", units.NewPx(10))
+	for i := 0; i < 3; i++ {
+		addTestBox(grid, "cell", 20, 20)
+		addTestBox(flow, "item", 20, 20)
+	}
+	grid.Child(0).SetProp("display", "none")
+	flow.Child(1).SetProp("display", "none")
+	vp.FullRender2DTree()
+	if c := grid.ChildAtGridPos(0, 0); c != grid.Child(1) {
+		t.Errorf("grid cell 0, 0: %v, expected the first shown child", c)
+	}
+	if grid.GridSize != image.Pt(2, 1) {
+		t.Errorf("grid size: %v, expected 2 x 1 for the shown children", grid.GridSize)
+	}
+	if x := flow.Child(2).(Node2D).AsWidget().LayState.Alloc.PosRel.X; x != 30 {
+		t.Errorf("flow item after collapsed one at: %v, expected 30", x)
+	}
+}
+
+func TestLayoutVisibilityHidden(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "col", LayoutVert)
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	mid := ly.Child(1).(Node2D).AsWidget()
+	last := ly.Child(2).(Node2D).AsWidget()
+	mid.SetProp("visibility", "hidden")
+	vp.FullRender2DTree()
+
+	if !mid.IsHidden() || mid.IsCollapsed() {
+		t.Fatalf("visibility: hidden not set")
+	}
+	if mid.LayState.Alloc.Size.Y != 20 || last.LayState.Alloc.PosRel.Y != 40 {
+		t.Errorf("hidden child should keep its space, size: %v, next pos: %v", mid.LayState.Alloc.Size.Y, last.LayState.Alloc.PosRel.Y)
+	}
+	ly.Render2DChildren() // viewport is not visible without a window, so call directly
+	if !mid.IsInvisible() || last.IsInvisible() {
+		t.Errorf("only the hidden child should be skipped for rendering")
+	}
+
+	mid.SetProp("visibility", "visible")
+	vp.FullRender2DTree()
+	if mid.IsHidden() || mid.IsInvisible() {
+		t.Errorf("visibility: visible did not restore")
+	}
+}
+
+func TestLayoutRounding(t *testing.T) {
+	defer func() { LayoutRounding = RoundPosSize }()
+	// 8px boxes at fractional DPI scales, specified directly in dots as the
+	// DPI comes from the window
+	for _, scale := range []float32{1.25, 1.3, 1.5, 1.75} {
+		for rnd := RoundPosSize; rnd < LayoutRoundingsN; rnd++ {
+			LayoutRounding = rnd
+			testLayoutRoundingTiles(t, rnd, 8*scale)
+		}
+	}
+}
+
+func testLayoutRoundingTiles(t *testing.T, rnd LayoutRoundings, sz float32) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "row", LayoutHoriz)
+	for i := 0; i < 5; i++ {
+		sp := AddNewSpace(ly, "box")
+		sp.SetFixedWidth(units.NewValue(sz, units.Dot))
+		sp.SetFixedHeight(units.NewValue(sz, units.Dot))
+	}
+	vp.FullRender2DTree()
+	for i := 1; i < len(ly.Kids); i++ {
+		prv := ly.Child(i - 1).(Node2D).AsWidget().VpBBox
+		cur := ly.Child(i).(Node2D).AsWidget().VpBBox
+		if rnd == RoundPosSize { // always covers allocation, may overlap
+			if prv.Max.X < cur.Min.X {
+				t.Errorf("%v: size %v: gap between child %d: %v and %v", rnd, sz, i, prv, cur)
+			}
+			continue
+		}
+		if prv.Max.X != cur.Min.X {
+			t.Errorf("%v: size %v: child %d does not tile: %v and %v", rnd, sz, i, prv, cur)
+		}
+	}
+}
+
+func TestLayoutApplyStyleToChildren(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "col", LayoutVert)
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	own := addTestBox(ly, "own", 20, 20)
+	own.SetProp("padding", units.NewPx(2))
+	vp.FullRender2DTree()
+
+	ly.ApplyStyleToChildren(ki.Props{"padding": units.NewPx(5)})
+	vp.FullRender2DTree()
+	for i := 0; i < 3; i++ {
+		ni := ly.Child(i).(Node2D).AsWidget()
+		if pad := ni.Sty.Layout.Padding.Dots; pad != 5 {
+			t.Errorf("child %d padding: %v, expected 5", i, pad)
+		}
+	}
+	if pad := own.Sty.Layout.Padding.Dots; pad != 2 {
+		t.Errorf("overridden child padding: %v, expected to keep 2", pad)
+	}
+
+	// a second call updates the values set by the first one
+	ly.ApplyStyleToChildren(ki.Props{"padding": units.NewPx(8)})
+	vp.FullRender2DTree()
+	for i := 0; i < 3; i++ {
+		ni := ly.Child(i).(Node2D).AsWidget()
+		if pad := ni.Sty.Layout.Padding.Dots; pad != 8 {
+			t.Errorf("child %d padding after second call: %v, expected 8", i, pad)
+		}
+	}
+	if pad := own.Sty.Layout.Padding.Dots; pad != 2 {
+		t.Errorf("overridden child padding after second call: %v, expected to keep 2", pad)
+	}
+}
+
+// testScrollLayout returns a fixed 100x100 layout within a viewport,
+// containing a single box of given size
+func testScrollLayout(w, h float32) (*Viewport2D, *Layout) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "scroll", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	addTestBox(ly, "box", w, h)
+	return vp, ly
+}
+
+func TestLayoutScrollClip(t *testing.T) {
+	// only a vertical scrollbar: clipped on the right
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] || ly.HasScroll[mat32.X] {
+		t.Fatalf("expected only V scroll, has: %v", ly.HasScroll)
+	}
+	sbw := int(ly.Sty.Layout.ScrollBarWidth.Dots)
+	full := ly.ChildrenBBox2DWidget()
+	cb := ly.ChildrenBBox2D()
+	if cb.Min != full.Min || cb.Max.Y != full.Max.Y || cb.Max.X != full.Max.X-sbw {
+		t.Errorf("V scroll clip: %v, full: %v, expected right edge reduced by %v", cb, full, sbw)
+	}
+
+	// only a horizontal scrollbar: clipped on the bottom
+	vp, ly = testScrollLayout(300, 50)
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.X] || ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected only H scroll, has: %v", ly.HasScroll)
+	}
+	full = ly.ChildrenBBox2DWidget()
+	cb = ly.ChildrenBBox2D()
+	if cb.Min != full.Min || cb.Max.X != full.Max.X || cb.Max.Y != full.Max.Y-sbw {
+		t.Errorf("H scroll clip: %v, full: %v, expected bottom edge reduced by %v", cb, full, sbw)
+	}
+}
+
+func TestLayoutScrollClipFractional(t *testing.T) {
+	// all sizes in dots at a 1.25 scale, so the scroll layout has a
+	// fractional position, size, padding and scrollbar width
+	scl := float32(1.25)
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutHoriz)
+	sp := AddNewSpace(outer, "pre")
+	sp.SetFixedWidth(units.NewValue(8.5*scl, units.Dot))
+	sp.SetFixedHeight(units.NewValue(10*scl, units.Dot))
+	ly := AddNewLayout(outer, "scroll", LayoutVert)
+	ly.SetFixedWidth(units.NewValue(80.3*scl, units.Dot))
+	ly.SetFixedHeight(units.NewValue(80.3*scl, units.Dot))
+	ly.SetProp("padding", units.NewValue(1.5*scl, units.Dot))
+	ly.SetProp("scrollbar-width", units.NewValue(10*scl, units.Dot))
+	box := AddNewSpace(ly, "box")
+	box.SetFixedWidth(units.NewValue(100*scl, units.Dot))
+	box.SetFixedHeight(units.NewValue(300*scl, units.Dot))
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.X] || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected both scrollbars, has: %v", ly.HasScroll)
+	}
+
+	pos := ly.LayState.Alloc.Pos
+	end := pos.Add(ly.LayState.Alloc.Size)
+	spc := ly.BoxSpace()
+	cb := ly.ChildrenBBox2D()
+	if float32(cb.Min.X) < pos.X+spc || float32(cb.Min.Y) < pos.Y+spc {
+		t.Errorf("clip: %v starts before content box at: %v", cb, pos.AddScalar(spc))
+	}
+	cend := end.SubScalar(spc).Sub(ly.ExtraSize)
+	if float32(cb.Max.X) > cend.X || float32(cb.Max.Y) > cend.Y {
+		t.Errorf("clip: %v extends past content box at: %v", cb, cend)
+	}
+	if bb := box.VpBBox; bb.Empty() || !bb.In(cb) {
+		t.Errorf("child box: %v not within clip: %v", bb, cb)
+	}
+}
+
+func TestLayoutMinFloor(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "row", LayoutHoriz)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 50, 20)
+	}
+	vp.FullRender2DTree()
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		if ni.LayState.Alloc.Size.X < 50 {
+			t.Errorf("child %d size: %v, expected at least min of 50", i, ni.LayState.Alloc.Size.X)
+		}
+	}
+	if !ly.HasScroll[mat32.X] {
+		t.Errorf("expected horizontal scrollbar for children overflowing their mins")
+	}
+}
+
+func TestStretchMinSize(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "row", LayoutHoriz)
+	ly.SetFixedWidth(units.NewPx(100))
+	addTestBox(ly, "left", 60, 20)
+	st := AddNewStretch(ly, "stretch")
+	st.SetProp("min-width", units.NewPx(20))
+	addTestBox(ly, "right", 60, 20)
+	vp.FullRender2DTree()
+	if sz := st.LayState.Alloc.Size.X; sz < 20 {
+		t.Errorf("stretch width in under-sized row: %v, expected at least min-width 20", sz)
+	}
+}
+
+func TestLayoutFlowJustify(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "flow", LayoutHorizFlow)
+	ly.SetFixedWidth(units.NewPx(200))
+	ly.SetProp("horizontal-align", gist.AlignJustify)
+	for i := 0; i < 5; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 60, 20)
+	}
+	vp.FullRender2DTree()
+	if len(ly.FlowBreaks) < 2 {
+		t.Fatalf("flow breaks: %v, expected the items to wrap", ly.FlowBreaks)
+	}
+	spc := ly.BoxSpace()
+	end := ly.LayState.Alloc.Size.X - spc
+	ci := 0
+	for li, bi := range ly.FlowBreaks {
+		first := ly.Child(ci).(*Space).LayState.Alloc
+		last := ly.Child(bi - 1).(*Space).LayState.Alloc
+		if first.PosRel.X != spc {
+			t.Errorf("line %d starts at: %v, expected %v", li, first.PosRel.X, spc)
+		}
+		lend := last.PosRel.X + last.Size.X
+		if li < len(ly.FlowBreaks)-1 {
+			if mat32.Abs(lend-end) > 0.01 {
+				t.Errorf("line %d ends at: %v, expected justified to %v", li, lend, end)
+			}
+		} else if lend >= end {
+			t.Errorf("last line ends at: %v, expected left-aligned short of %v", lend, end)
+		}
+		ci = bi
+	}
+}
+
+func TestLayoutScrollLeft(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	box := ly.Child(0).(Node2D).AsWidget()
+	rtX := box.LayState.Alloc.Pos.X
+
+	vp, ly = testScrollLayout(50, 300)
+	ly.VScrollLeft = true
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	sbw := ly.Sty.Layout.ScrollBarWidth.Dots
+	sc := ly.Scrolls[mat32.Y]
+	if sc.LayState.Alloc.Pos.X != ly.LayState.Alloc.Pos.X {
+		t.Errorf("left scrollbar pos: %v, expected layout pos: %v", sc.LayState.Alloc.Pos.X, ly.LayState.Alloc.Pos.X)
+	}
+	box = ly.Child(0).(Node2D).AsWidget()
+	if box.LayState.Alloc.Pos.X != rtX+sbw {
+		t.Errorf("content pos: %v, expected shifted right to: %v", box.LayState.Alloc.Pos.X, rtX+sbw)
+	}
+	full := ly.ChildrenBBox2DWidget()
+	cb := ly.ChildrenBBox2D()
+	if cb.Min.X != full.Min.X+int(sbw) || cb.Max.X != full.Max.X {
+		t.Errorf("left scroll clip: %v, full: %v, expected left edge moved by %v", cb, full, sbw)
+	}
+}
+
+func TestLayoutChildClipInScroll(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "scroll", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	fr := AddNewFrame(ly, "clip", LayoutVert) // first, so scrolling moves it partly out of view
+	addTestBox(ly, "box", 50, 300)
+	fr.SetFixedWidth(units.NewPx(80))
+	fr.SetFixedHeight(units.NewPx(80))
+	fr.SetProp("padding", units.NewPx(10))
+	fr.SetProp("overflow", gist.OverflowHidden)
+	in := addTestBox(fr, "in", 200, 200)
+	vp.FullRender2DTree()
+	ly.ScrollToPos(mat32.Y, 30)
+	vp.FullRender2DTree()
+
+	pcb := ly.ChildrenBBox2D()
+	fcb := fr.ChildrenObjBBox()
+	if fcb.Min.Y >= pcb.Min.Y {
+		t.Fatalf("frame content at: %v, expected scrolled above: %v", fcb, pcb)
+	}
+	if exp := fcb.Intersect(pcb); in.VpBBox != exp {
+		t.Errorf("child clip: %v, expected frame content box: %v within scroll: %v", in.VpBBox, exp, pcb)
+	}
+}
+
+func TestLayoutClipModifier(t *testing.T) {
+	vp, ly := testScrollLayout(80, 80)
+	ly.ClipModifier = func(bb image.Rectangle) image.Rectangle {
+		bb.Min.Y += 20 // pinned header region
+		return bb
+	}
+	vp.FullRender2DTree()
+	full := ly.ChildrenBBox2DWidget()
+	cb := ly.ChildrenBBox2D()
+	if cb.Min.Y != full.Min.Y+20 || cb.Max != full.Max || cb.Min.X != full.Min.X {
+		t.Errorf("modified clip: %v, full: %v, expected top reduced by 20", cb, full)
+	}
+	box := ly.Child(0).(Node2D).AsWidget()
+	if box.VpBBox.Min.Y != cb.Min.Y {
+		t.Errorf("child bbox: %v, expected clipped to: %v", box.VpBBox, cb)
+	}
+	if box.VpBBox.Max.Y != box.LayState.Alloc.Pos.ToPoint().Y+80 {
+		t.Errorf("child bbox: %v, expected bottom unclipped", box.VpBBox)
+	}
+}
+
+func TestLayoutVisibleChildren(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "col", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	for i := 0; i < 10; i++ {
+		addTestBox(ly, "box", 50, 20)
+	}
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	tests := []struct {
+		off   float32
+		num   int
+		first int
+	}{
+		{0, 5, 0},
+		{30, 4, 2},
+		{40, 5, 2},
+		{100, 5, 5},
+	}
+	for _, ts := range tests {
+		ly.ScrollToPos(mat32.Y, ts.off)
+		if n := ly.NumVisibleChildren(); n != ts.num {
+			t.Errorf("offset %v: num visible: %v, expected %v", ts.off, n, ts.num)
+		}
+		if fi := ly.FirstVisibleChildIdx(); fi != ts.first {
+			t.Errorf("offset %v: first visible: %v, expected %v", ts.off, fi, ts.first)
+		}
+	}
+
+	// wider than the layout: none fully visible
+	vp = testViewport(400, 300)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "col", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "wide", 500, 20)
+	}
+	vp.FullRender2DTree()
+	if n := ly.NumVisibleChildren(); n != 0 {
+		t.Errorf("num visible: %v, expected 0", n)
+	}
+	if fi := ly.FirstVisibleChildIdx(); fi != -1 {
+		t.Errorf("first visible: %v, expected -1", fi)
+	}
+}
+
+func TestLayoutRTLJustify(t *testing.T) {
+	lay := func(dir gist.TextDirections, n int) *Layout {
+		vp := testViewport(400, 300)
+		outer := AddNewLayout(vp, "outer", LayoutVert)
+		ly := AddNewLayout(outer, "row", LayoutHoriz)
+		ly.TextDir = dir
+		ly.SetFixedWidth(units.NewPx(200))
+		ly.SetProp("horizontal-align", gist.AlignJustify)
+		for i := 0; i < n; i++ {
+			addTestBox(ly, fmt.Sprintf("box%d", i), float32(10*(i+1)), 10)
+		}
+		vp.FullRender2DTree()
+		return ly
+	}
+	for _, n := range []int{1, 3} {
+		ltr, rtl := lay(gist.LTR, n), lay(gist.RTL, n)
+		w := ltr.LayState.Alloc.Size.X
+		for i := 0; i < n; i++ {
+			la := ltr.Child(i).(Node2D).AsWidget().LayState.Alloc
+			ra := rtl.Child(i).(Node2D).AsWidget().LayState.Alloc
+			if exp := w - la.PosRel.X - la.Size.X; ra.PosRel.X != exp || ra.Size != la.Size {
+				t.Errorf("%d kids: RTL child %d at: %v size: %v, expected mirror of LTR at: %v", n, i, ra.PosRel.X, ra.Size, exp)
+			}
+		}
+		// the first child anchors at the right edge
+		fa := rtl.Child(0).(Node2D).AsWidget().LayState.Alloc
+		if end := fa.PosRel.X + fa.Size.X; end != w-rtl.BoxSpace() {
+			t.Errorf("%d kids: RTL first child ends at: %v, expected at right edge: %v", n, end, w-rtl.BoxSpace())
+		}
+	}
+}
+
+func TestLayoutOnResize(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "frame", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(50))
+	var olds, nws []mat32.Vec2
+	ly.OnResize(func(old, nw mat32.Vec2) {
+		olds = append(olds, old)
+		nws = append(nws, nw)
+	})
+	vp.FullRender2DTree()
+	if len(nws) != 1 || olds[0] != mat32.Vec2Zero || nws[0] != mat32.NewVec2(100, 50) {
+		t.Fatalf("first layout calls: old %v new %v, expected one from 0 to (100, 50)", olds, nws)
+	}
+
+	// simulated passes: sub-pixel jitter is ignored
+	for _, sz := range []mat32.Vec2{mat32.NewVec2(100.4, 50), mat32.NewVec2(100, 50.5), mat32.NewVec2(130, 50), mat32.NewVec2(130, 50), mat32.NewVec2(130, 80)} {
+		ly.LayState.Alloc.Size = sz
+		ly.CheckResize()
+	}
+	expOld := []mat32.Vec2{mat32.NewVec2(0, 0), mat32.NewVec2(100, 50), mat32.NewVec2(130, 50)}
+	expNew := []mat32.Vec2{mat32.NewVec2(100, 50), mat32.NewVec2(130, 50), mat32.NewVec2(130, 80)}
+	if len(nws) != len(expNew) {
+		t.Fatalf("calls: old %v new %v, expected old %v new %v", olds, nws, expOld, expNew)
+	}
+	for i := range expNew {
+		if olds[i] != expOld[i] || nws[i] != expNew[i] {
+			t.Errorf("call %d: old %v new %v, expected old %v new %v", i, olds[i], nws[i], expOld[i], expNew[i])
+		}
+	}
+}
+
+func TestLayoutBreakpoint(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "resp", LayoutVert)
+	ly.SetStretchMaxWidth()
+	ly.SetBreakpoint(300, LayoutVert, LayoutHoriz)
+	b0 := addTestBox(ly, "box0", 20, 20)
+	b1 := addTestBox(ly, "box1", 20, 20)
+	vp.FullRender2DTree()
+	if ly.Lay != LayoutHoriz {
+		t.Fatalf("layout at width: %v is: %v, expected LayoutHoriz above the breakpoint", ly.LayState.Alloc.Size.X, ly.Lay)
+	}
+	p0, p1 := b0.LayState.Alloc.PosRel, b1.LayState.Alloc.PosRel
+	if p1.X <= p0.X || p1.Y != p0.Y {
+		t.Errorf("box0 at: %v, box1 at: %v, expected laid out in a row", p0, p1)
+	}
+
+	// simulated resize passes, with the hysteresis band at 290..310
+	for _, st := range []struct {
+		width float32
+		exp   Layouts
+	}{{305, LayoutHoriz}, {295, LayoutHoriz}, {285, LayoutVert}, {295, LayoutVert}, {305, LayoutVert}, {315, LayoutHoriz}} {
+		ly.LayState.Alloc.Size.X = st.width
+		ly.ApplyBreakpoint()
+		if ly.Lay != st.exp {
+			t.Errorf("width: %v layout: %v, expected: %v", st.width, ly.Lay, st.exp)
+		}
+	}
+	// re-styling resets the type to the lay property, but not the side
+	ly.SetProp("lay", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(315))
+	vp.FullRender2DTree()
+	ly.SetFixedWidth(units.NewPx(305))
+	vp.FullRender2DTree()
+	if ly.Lay != LayoutHoriz {
+		t.Errorf("re-styled layout at width: %v is: %v, expected LayoutHoriz within the band", ly.LayState.Alloc.Size.X, ly.Lay)
+	}
+}
+
+func TestLayoutRebuildPreservingFocus(t *testing.T) {
+	vp := testWindowViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "frame", LayoutVert)
+	build := func(names ...string) {
+		for _, nm := range names {
+			addTestBox(ly, nm, 20, 20).SetCanFocus()
+		}
+	}
+	build("a", "b")
+	vp.FullRender2DTree()
+	em := ly.EventMgr2D()
+	if em == nil {
+		t.Fatalf("expected an EventMgr from the window")
+	}
+	em.SetFocus(ly.ChildByName("b", 0))
+	// the old focus can be destroyed, so only use its name
+	focName := func() string {
+		if foc := em.CurFocus(); foc != nil {
+			return foc.Name()
+		}
+		return "none"
+	}
+
+	ly.RebuildPreservingFocus(func() {
+		ly.Clear()
+		build("a", "b", "c")
+	})
+	nb := ly.ChildByName("b", 0)
+	if foc := em.CurFocus(); foc != nb || !nb.(Node2D).AsNode2D().HasFocus() {
+		t.Errorf("focus after rebuild: %v, expected the new b", focName())
+	}
+
+	// no equivalent widget: the deleted widget does not keep the focus
+	ly.RebuildPreservingFocus(func() {
+		ly.Clear()
+		build("x")
+	})
+	if foc := em.CurFocus(); foc != nil {
+		t.Errorf("focus after rebuild without b: %v, expected none", focName())
+	}
+}
+
+func TestLayoutKineticScroll(t *testing.T) {
+	vp, ly := testScrollLayout(50, 2000)
+	ly.KineticScroll = true
+	vp.FullRender2DTree()
+	sc := ly.Scrolls[mat32.Y]
+	if !ly.HasScroll[mat32.Y] || sc == nil {
+		t.Fatalf("expected V scroll")
+	}
+	ly.KineticVel = mat32.NewVec2(0, 1000)
+	dt := float32(1) / 60
+	prev, prevDel := sc.Value, float32(-1)
+	steps := 0
+	for ly.KineticStep(dt) {
+		del := sc.Value - prev
+		if del <= 0 || (prevDel >= 0 && del > prevDel) {
+			t.Fatalf("step %d scrolled: %v after: %v, expected to decelerate monotonically", steps, del, prevDel)
+		}
+		prev, prevDel = sc.Value, del
+		steps++
+		if steps > 1000 {
+			t.Fatalf("kinetic scroll did not stop, vel: %v", ly.KineticVel)
+		}
+	}
+	if steps < 10 || sc.Value >= sc.Max-sc.ThumbVal {
+		t.Errorf("stopped after %d steps at: %v, expected to coast and stop before the end", steps, sc.Value)
+	}
+
+	// a fast flick stops at the end of the range
+	ly.KineticVel = mat32.NewVec2(0, 1e5)
+	for steps = 0; ly.KineticStep(dt) && steps < 1000; steps++ {
+	}
+	if end := sc.Max - sc.ThumbVal; sc.Value != end || !ly.KineticVel.IsNil() {
+		t.Errorf("flick stopped at: %v vel: %v, expected at end: %v", sc.Value, ly.KineticVel, end)
+	}
+}
+
+func TestLayoutOverlayScroll(t *testing.T) {
+	vp, ly := testScrollLayout(300, 300)
+	ly.OverlayScroll = true
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.X] || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected both scrollbars, has: %v", ly.HasScroll)
+	}
+	if ly.ExtraSize != mat32.Vec2Zero {
+		t.Errorf("overlay extra size: %v, expected zero", ly.ExtraSize)
+	}
+	full := ly.ChildrenBBox2DWidget()
+	if cb := ly.ChildrenBBox2D(); cb != full {
+		t.Errorf("overlay clip: %v, expected full size: %v", cb, full)
+	}
+	// scrollbars lie over the content edge
+	sc := ly.Scrolls[mat32.Y]
+	if sb := sc.VpBBox; !sb.Overlaps(full) || sb.Max.X > full.Max.X {
+		t.Errorf("overlay scrollbar bbox: %v, expected within content: %v", sb, full)
+	}
+	me := &mouse.ScrollEvent{Delta: image.Pt(0, 20)}
+	ly.ScrollDelta(me)
+	if ly.Scrolls[mat32.Y].Value != 20 {
+		t.Errorf("overlay scroll value: %v, expected 20", ly.Scrolls[mat32.Y].Value)
+	}
+}
+
+// testPopupFrame returns an unparented frame of given size, for anchoring
+func testPopupFrame(w, h float32) *Frame {
+	fr := &Frame{}
+	fr.InitName(fr, "popup")
+	fr.LayState.Alloc.Size = mat32.NewVec2(w, h)
+	return fr
+}
+
+func TestLayoutAnchorTo(t *testing.T) {
+	vp := testViewport(200, 200)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	addTestBox(outer, "above", 40, 150)
+	row := AddNewLayout(outer, "row", LayoutHoriz)
+	bef := addTestBox(row, "before", 80, 20)
+	trg := addTestBox(row, "target", 40, 20)
+	vp.FullRender2DTree()
+	if exp := image.Rect(80, 150, 120, 170); trg.WinBBox != exp {
+		t.Fatalf("target bbox: %v, expected %v", trg.WinBBox, exp)
+	}
+
+	fr := testPopupFrame(50, 30)
+	sides := []struct {
+		side gist.Align
+		pos  mat32.Vec2
+	}{
+		{gist.AlignBottom, mat32.NewVec2(80, 170)},
+		{gist.AlignTop, mat32.NewVec2(80, 120)},
+		{gist.AlignLeft, mat32.NewVec2(30, 150)},
+		{gist.AlignRight, mat32.NewVec2(120, 150)},
+	}
+	for _, sd := range sides {
+		fr.AnchorTo(trg, sd.side, true)
+		if fr.LayState.Alloc.Pos != sd.pos {
+			t.Errorf("side %v pos: %v, expected %v", sd.side, fr.LayState.Alloc.Pos, sd.pos)
+		}
+	}
+
+	// near the bottom edge of the window: flips above
+	fr = testPopupFrame(50, 40)
+	fr.AnchorTo(trg, gist.AlignBottom, false)
+	if exp := mat32.NewVec2(80, 170); fr.LayState.Alloc.Pos != exp {
+		t.Errorf("no flip pos: %v, expected %v", fr.LayState.Alloc.Pos, exp)
+	}
+	fr.AnchorTo(trg, gist.AlignBottom, true)
+	if exp := mat32.NewVec2(80, 110); fr.LayState.Alloc.Pos != exp {
+		t.Errorf("flip to top pos: %v, expected %v", fr.LayState.Alloc.Pos, exp)
+	}
+
+	// near the left edge of the window: flips right
+	fr = testPopupFrame(50, 30)
+	fr.AnchorTo(bef, gist.AlignLeft, true)
+	if exp := mat32.NewVec2(80, 150); fr.LayState.Alloc.Pos != exp {
+		t.Errorf("flip to right pos: %v, expected %v", fr.LayState.Alloc.Pos, exp)
+	}
+
+	// taller than the space below the target: shifted up to stay within bounds
+	fr = testPopupFrame(50, 180)
+	fr.AnchorTo(trg, gist.AlignRight, true)
+	if exp := mat32.NewVec2(120, 20); fr.LayState.Alloc.Pos != exp {
+		t.Errorf("clamped pos: %v, expected %v", fr.LayState.Alloc.Pos, exp)
+	}
+}
+
+func TestLayoutMeasureOnly(t *testing.T) {
+	for _, lay := range []Layouts{LayoutVert, LayoutGrid} {
+		vp := testViewport(400, 300)
+		ly := AddNewLayout(vp, "ly", lay)
+		ly.SetProp("columns", 2)
+		for i := 0; i < 4; i++ {
+			addTestBox(ly, "box", 20+float32(i)*10, 20)
+		}
+		vp.FullRender2DTree()
+		lst := ly.LayState
+		kst := make([]LayoutState, len(ly.Kids))
+		for i, k := range ly.Kids {
+			kst[i] = k.(Node2D).AsWidget().LayState
+		}
+
+		pref := ly.MeasureOnly()
+		if ly.LayState != lst {
+			t.Errorf("%v: layout state changed: %v, was: %v", lay, ly.LayState, lst)
+		}
+		for i, k := range ly.Kids {
+			if ls := k.(Node2D).AsWidget().LayState; ls != kst[i] {
+				t.Errorf("%v: child %d state changed: %v, was: %v", lay, i, ls, kst[i])
+			}
+		}
+		ly.Size2D(0)
+		if pref != ly.LayState.Size.Pref {
+			t.Errorf("%v: measured pref: %v, expected: %v", lay, pref, ly.LayState.Size.Pref)
+		}
+	}
+}
+
+func TestLayoutMinContentSize(t *testing.T) {
+	vp := testViewport(400, 300)
+	col := AddNewLayout(vp, "col", LayoutVert)
+	col.SetProp("spacing", units.NewPx(4))
+	addTestBox(col, "a", 20, 10)
+	addTestBox(col, "b", 30, 15)
+	row := AddNewLayout(col, "row", LayoutHoriz)
+	row.SetProp("spacing", units.NewPx(2))
+	addTestBox(row, "c", 10, 10)
+	addTestBox(row, "d", 25, 12)
+	testSizeTree(vp)
+	lst := col.LayState
+
+	if sz := row.MinContentSize(); sz != mat32.NewVec2(37, 12) {
+		t.Errorf("row min content size: %v, expected (37, 12)", sz)
+	}
+	var exp mat32.Vec2
+	for _, k := range col.Kids {
+		ni := k.(Node2D).AsWidget()
+		exp.X = mat32.Max(exp.X, ni.LayState.Size.Need.X)
+		exp.Y += k.(Node2D).MinContentSize().Y
+	}
+	exp.Y += 2 * 4
+	if sz := col.MinContentSize(); sz != exp {
+		t.Errorf("col min content size: %v, expected children's min plus spacing: %v", sz, exp)
+	}
+	if col.LayState != lst {
+		t.Errorf("layout state changed by MinContentSize")
+	}
+}
+
+func TestLayoutScrollNearEnd(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	nfire := 0
+	ly.OnScrollNearEnd(20, func() {
+		nfire++
+	})
+	sc := ly.Scrolls[mat32.Y]
+	end := sc.Max - sc.ThumbVal
+
+	vals := []float32{50, end - 10, end, end - 5, 0, end}
+	exp := []int{0, 1, 1, 1, 1, 2}
+	for i, v := range vals {
+		ly.ScrollToPos(mat32.Y, v)
+		if nfire != exp[i] {
+			t.Errorf("scroll to %v: fired %v times, expected %v", v, nfire, exp[i])
+		}
+	}
+}
+
+func TestLayoutScrollClampShrink(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	sc := ly.Scrolls[mat32.Y]
+	if sc == nil || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	ly.ScrollToPos(mat32.Y, sc.Max)
+	oend := sc.Max - sc.ThumbVal
+	if sc.Value != oend {
+		t.Fatalf("scroll value: %v, expected at bottom: %v", sc.Value, oend)
+	}
+
+	box := ly.Child(0).(*Space)
+	box.SetFixedHeight(units.NewPx(200))
+	vp.FullRender2DTree()
+	end := sc.Max - sc.ThumbVal
+	if end >= oend {
+		t.Fatalf("scroll range end: %v, expected to shrink from: %v", end, oend)
+	}
+	if sc.Value != end {
+		t.Errorf("scroll value after shrink: %v, expected pinned to new bottom: %v", sc.Value, end)
+	}
+	// bottom of content at bottom of layout: no blank space
+	bot := box.LayState.Alloc.Pos.Y + box.LayState.Alloc.Size.Y
+	if cb := ly.ChildrenBBox2D(); mat32.Abs(bot-float32(cb.Max.Y)) > 1 {
+		t.Errorf("content bottom: %v, expected at layout bottom: %v", bot, cb.Max.Y)
+	}
+
+	box.SetFixedHeight(units.NewPx(400))
+	vp.FullRender2DTree()
+	if end = sc.Max - sc.ThumbVal; sc.Value != end {
+		t.Errorf("scroll value after grow: %v, expected pinned to new bottom: %v", sc.Value, end)
+	}
+
+	ly.ScrollToPos(mat32.Y, 50)
+	box.SetFixedHeight(units.NewPx(300))
+	vp.FullRender2DTree()
+	if sc.Value != 50 {
+		t.Errorf("scroll value: %v, expected to stay at 50 when not at bottom", sc.Value)
+	}
+}
+
+func TestLayoutSetLayout(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "view", LayoutGrid)
+	ly.SetProp("columns", 2)
+	for i := 0; i < 4; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ly.GridSize != image.Pt(2, 2) || len(ly.GridData[Row]) != 2 {
+		t.Fatalf("grid size: %v, expected 2x2", ly.GridSize)
+	}
+
+	ly.SetLayout(LayoutVert)
+	vp.FullRender2DTree()
+	if ly.Lay != LayoutVert {
+		t.Errorf("layout: %v, expected LayoutVert", ly.Lay)
+	}
+	if ly.GridData[Row] != nil || ly.GridData[Col] != nil || ly.GridSize != image.ZP {
+		t.Errorf("grid data not cleared: size: %v rows: %v cols: %v", ly.GridSize, len(ly.GridData[Row]), len(ly.GridData[Col]))
+	}
+	spc := ly.Spacing.Dots
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		pr := ni.LayState.Alloc.PosRel
+		if pr.Y != float32(i)*(20+spc) || pr.X != 0 {
+			t.Errorf("child %d at: %v, expected stacked vertically at y: %v", i, pr, float32(i)*(20+spc))
+		}
+	}
+}
+
+func TestLayoutCenterWhenFits(t *testing.T) {
+	vp, ly := testScrollLayout(50, 40)
+	ly.CenterWhenFits = true
+	vp.FullRender2DTree()
+	box := ly.Child(0).(*Space)
+	if y := box.LayState.Alloc.PosRel.Y; y != 30 {
+		t.Errorf("short content at: %v, expected centered at 30", y)
+	}
+	if ly.HasScroll[mat32.Y] {
+		t.Errorf("short content should not scroll")
+	}
+
+	box.SetFixedHeight(units.NewPx(300))
+	vp.FullRender2DTree()
+	if y := box.LayState.Alloc.PosRel.Y; y != 0 {
+		t.Errorf("tall content at: %v, expected top-aligned at 0", y)
+	}
+	if !ly.HasScroll[mat32.Y] {
+		t.Errorf("tall content should scroll")
+	}
+
+	// horizontal layout: children centered as a group
+	row := AddNewLayout(ly.Parent(), "row", LayoutHoriz)
+	row.SetFixedWidth(units.NewPx(100))
+	row.SetFixedHeight(units.NewPx(100))
+	row.CenterWhenFits = true
+	addTestBox(row, "short", 20, 20)
+	addTestBox(row, "tall", 20, 40)
+	vp.FullRender2DTree()
+	st, ed := float32(100), float32(0)
+	for _, k := range row.Kids {
+		ni := k.(Node2D).AsWidget()
+		st = mat32.Min(st, ni.LayState.Alloc.PosRel.Y)
+		ed = mat32.Max(ed, ni.LayState.Alloc.PosRel.Y+ni.LayState.Alloc.Size.Y)
+	}
+	if st != 30 || ed != 70 {
+		t.Errorf("row children span: %v - %v, expected centered at 30 - 70", st, ed)
+	}
+}
+
+func TestLayoutStretchBesideScroll(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	box := ly.Child(0).(*Space)
+	box.SetStretchMaxWidth()
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	if ly.HasScroll[mat32.X] {
+		t.Errorf("unexpected H scroll from stretching under the V scrollbar")
+	}
+	sbw := ly.Sty.Layout.ScrollBarWidth.Dots
+	if w := box.LayState.Alloc.Size.X; w != 100-sbw {
+		t.Errorf("stretched width: %v, expected to fit beside scrollbar: %v", w, 100-sbw)
+	}
+	if ly.ChildSize.X > 100-sbw {
+		t.Errorf("content width: %v overflows beside scrollbar: %v", ly.ChildSize.X, 100-sbw)
+	}
+}
+
+func TestLayoutStretchEqual(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "row", LayoutHoriz)
+	ly.SetFixedWidth(units.NewPx(300))
+	var kids []*Space
+	for _, w := range []float32{20, 60} {
+		sp := AddNewSpace(ly, "stretch")
+		sp.SetProp("width", units.NewPx(w))
+		sp.SetProp("min-width", units.NewPx(w))
+		sp.SetStretchMaxWidth()
+		kids = append(kids, sp)
+	}
+	added := func() (a0, a1 float32) {
+		vp.FullRender2DTree()
+		a0 = kids[0].LayState.Alloc.Size.X - kids[0].LayState.Size.Pref.X
+		a1 = kids[1].LayState.Alloc.Size.X - kids[1].LayState.Size.Pref.X
+		return
+	}
+
+	a0, a1 := added()
+	if a0 <= 0 || mat32.Abs(a1-3*a0) > 0.01 {
+		t.Errorf("proportional added space: %v, %v, expected in proportion to pref 20:60", a0, a1)
+	}
+	ly.SetProp("stretch-mode", "equal")
+	a0, a1 = added()
+	if ly.StretchMode != StretchEqual {
+		t.Errorf("stretch mode: %v, expected StretchEqual", ly.StretchMode)
+	}
+	if a0 <= 0 || a0 != a1 {
+		t.Errorf("equal added space: %v, %v, expected the same", a0, a1)
+	}
+}
+
+func TestLayoutComputePreferredColumns(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "lay", LayoutVert)
+	ly.SetProp("spacing", units.NewPx(10))
+	for i, w := range []float32{50, 30, 40, 20} {
+		addTestBox(ly, fmt.Sprintf("box%d", i), w, 20)
+	}
+	testSizeTree(vp)
+	lst := ly.LayState
+	for _, tc := range []struct {
+		avail float32
+		cols  int
+	}{{10, 1}, {89, 1}, {90, 2}, {139, 2}, {140, 3}, {169, 3}, {170, 4}, {1000, 4}} {
+		if cols := ly.ComputePreferredColumns(tc.avail); cols != tc.cols {
+			t.Errorf("columns for width %v: %v, expected %v", tc.avail, cols, tc.cols)
+		}
+	}
+	if ly.LayState != lst {
+		t.Errorf("layout state changed by ComputePreferredColumns")
+	}
+}
+
+func TestGridDataReuse(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 4)
+	for i := 0; i < 12; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	testSizeTree(vp)
+	rd := &ly.GridData[Row][0]
+	ly.GridData[Row][0].SizeMax = -1
+
+	ly.Sty.Layout.Columns = 6 // 2 rows, within capacity
+	GatherSizesGrid(ly)
+	if len(ly.GridData[Row]) != 2 || &ly.GridData[Row][0] != rd {
+		t.Errorf("row grid data was reallocated when shrinking")
+	}
+	if ly.GridData[Row][0].SizeMax < 0 {
+		t.Errorf("row SizeMax not reset: %v", ly.GridData[Row][0].SizeMax)
+	}
+}
+
+func BenchmarkGatherSizesGridResize(b *testing.B) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 6)
+	for i := 0; i < 36; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	testSizeTree(vp)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ly.Sty.Layout.Columns = 3 + i%4
+		GatherSizesGrid(ly)
+	}
+}
+
+func TestGridSizeCache(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "grid", LayoutGrid)
+	ly.SetProp("columns", 3)
+	for i := 0; i < 9; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	testSizeTree(vp)
+	if !ly.GridCache.Valid {
+		t.Fatalf("grid size cache not valid after size pass")
+	}
+	ly.GridData[Row][0].SizeNeed = -5 // only kept if the gather is skipped
+	vp.Size2DTree(0)
+	if ly.GridData[Row][0].SizeNeed != -5 {
+		t.Errorf("grid sizes re-gathered with nothing changed")
+	}
+	if ly.GridSize != image.Pt(3, 3) || ly.LayState.Size.Pref != mat32.NewVec2(60, 60) {
+		t.Errorf("cached grid size: %v pref: %v, expected 3 x 3 and (60, 60)", ly.GridSize, ly.LayState.Size.Pref)
+	}
+
+	kst := &ly.Child(0).(Node2D).AsWidget().LayState
+	kst.Size.Pref.Y = 50
+	kst.Size.Max.Y = 50
+	ly.Size2D(0)
+	if sn := ly.GridData[Row][0].SizeNeed; sn == -5 || ly.GridData[Row][0].SizePref != 50 {
+		t.Errorf("grid sizes not re-gathered after child size change: need: %v pref: %v", sn, ly.GridData[Row][0].SizePref)
+	}
+
+	// every grid style input invalidates the cache
+	for nm, set := range map[string]func(){
+		"auto-fit-min": func() { ly.AutoFitMin.Dots = 30 },
+		"areas":        func() { ly.GridTemplateAreas = []string{"a a ."} },
+		"col tracks":   func() { ly.GridTemplateCols = []GridTrack{{Min: units.NewPx(40)}} },
+		"align items":  func() { ly.GridAlignItems = GridItemsEnd },
+		"row aligns":   func() { ly.GridRowAligns = []GridItemAligns{GridItemsEnd} },
+	} {
+		ly.Size2D(0)
+		ly.GridData[Row][0].SizeNeed = -5
+		set()
+		ly.Size2D(0)
+		if ly.GridData[Row][0].SizeNeed == -5 {
+			t.Errorf("grid sizes not re-gathered after %v change", nm)
+		}
+	}
+}
+
+func benchmarkGridScroll(b *testing.B, cache bool) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	ly.SetProp("columns", 6)
+	for i := 0; i < 60; i++ {
+		addTestBox(ly, "box", 20, 20)
+	}
+	vp.FullRender2DTree()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ly.ScrollActionDelta(mat32.Y, float32(1-2*(i%2)))
+		if !cache {
+			ly.GridCache.Valid = false
+		}
+		vp.Size2DTree(0)
+	}
+}
+
+func BenchmarkGridSizeScrollCached(b *testing.B) {
+	benchmarkGridScroll(b, true)
+}
+
+func BenchmarkGridSizeScrollNoCache(b *testing.B) {
+	benchmarkGridScroll(b, false)
+}
+
+func TestLayoutBoxSpaceCache(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "col", LayoutVert)
+	addTestBox(ly, "box", 20, 20)
+	vp.FullRender2DTree()
+	if bs := ly.BoxSpace(); bs != ly.Sty.BoxSpace() {
+		t.Errorf("cached box space: %v, expected: %v", bs, ly.Sty.BoxSpace())
+	}
+	prv := ly.BoxSpace()
+	ly.SetProp("padding", units.NewPx(10))
+	vp.FullRender2DTree()
+	bs := ly.BoxSpace()
+	if bs != ly.Sty.BoxSpace() || bs != prv+10 {
+		t.Errorf("box space after style update: %v, expected: %v", bs, ly.Sty.BoxSpace())
+	}
+	box := ly.Child(0).(Node2D).AsWidget()
+	if box.LayState.Alloc.PosRel.Y != bs {
+		t.Errorf("child pos: %v, expected box space: %v", box.LayState.Alloc.PosRel.Y, bs)
+	}
+}
+
+// testDeepTree returns a viewport containing nested layouts of given depth,
+// each with given number of children
+func testDeepTree(depth, n int) *Viewport2D {
+	vp := testViewport(800, 800)
+	lays := []*Layout{AddNewLayout(vp, "top", LayoutVert)}
+	for d := 0; d < depth; d++ {
+		var nxt []*Layout
+		for li, par := range lays {
+			for i := 0; i < n; i++ {
+				if d == depth-1 {
+					addTestBox(par, "box", 4, 4)
+					continue
+				}
+				lay := LayoutHoriz
+				if (d+li)%2 == 1 {
+					lay = LayoutVert
+				}
+				nxt = append(nxt, AddNewLayout(par, "ly", lay))
+			}
+		}
+		lays = nxt
+	}
+	return vp
+}
+
+func BenchmarkLayoutDeepTree(b *testing.B) {
+	vp := testDeepTree(5, 4)
+	testSizeTree(vp)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vp.Size2DTree(0)
+		vp.Layout2DTree()
+	}
+}
+
+func TestLayoutRelayout(t *testing.T) {
+	vp, ly := testScrollLayout(50, 50)
+	vp.FullRender2DTree()
+	if ly.HasScroll[mat32.Y] {
+		t.Fatalf("unexpected V scroll")
+	}
+	box := ly.Child(0).(*Space)
+	box.Sty.Layout.Height = units.NewPx(300)
+	box.Sty.Layout.Height.ToDots(&box.Sty.UnContext)
+	box.Sty.Layout.MinHeight = box.Sty.Layout.Height
+	box.Sty.Layout.MaxHeight = box.Sty.Layout.Height
+	ly.Relayout() // note: render turns off the scrolls without a window
+	if _, v := ly.ScrollBarsActive(); !v {
+		t.Errorf("expected V scroll after Relayout with taller child")
+	}
+	if sz := box.LayState.Alloc.Size.Y; sz != 300 {
+		t.Errorf("child height after Relayout: %v, expected 300", sz)
+	}
+}
+
+func TestLayoutStateEquals(t *testing.T) {
+	ld := LayoutState{}
+	ld.Alloc.Pos = mat32.NewVec2(10, 20)
+	ld.Alloc.Size = mat32.NewVec2(100, 50)
+	ot := ld
+	ot.Alloc.Size.X += LayoutEqualTol / 10
+	ot.Alloc.Pos.Y -= LayoutEqualTol / 10
+	if !ld.Equals(&ot) {
+		t.Errorf("sub-tolerance jitter: %v vs. %v, expected equal", ld.Alloc, ot.Alloc)
+	}
+	ot.Alloc.Size.X += 10 * LayoutEqualTol
+	if ld.Equals(&ot) {
+		t.Errorf("size change: %v vs. %v, expected not equal", ld.Alloc, ot.Alloc)
+	}
+
+	// the previous allocation is kept across layout passes
+	vp, ly := testScrollLayout(50, 50)
+	vp.FullRender2DTree()
+	box := ly.Child(0).(*Space)
+	vp.FullRender2DTree()
+	if box.LayState.AllocChanged() {
+		t.Errorf("unchanged layout: %v, prev: %v, expected no change", box.LayState.Alloc, box.LayState.Prev)
+	}
+	box.SetFixedWidth(units.NewPx(80))
+	vp.FullRender2DTree()
+	if !box.LayState.AllocChanged() || box.LayState.Prev.Size.X != 50 {
+		t.Errorf("resized layout: %v, prev: %v, expected change from width 50", box.LayState.Alloc, box.LayState.Prev)
+	}
+
+	// a wrapping flow takes a second (redo) iteration within each pass,
+	// which does not count as a previous pass
+	vp = testViewport(200, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	flow := AddNewLayout(outer, "flow", LayoutHorizFlow)
+	for i := 0; i < 10; i++ {
+		addTestBox(flow, fmt.Sprintf("box%d", i), 60, 20)
+	}
+	vp.FullRender2DTree()
+	vp.FullRender2DTree()
+	if flow.LayState.AllocChanged() {
+		t.Errorf("unchanged flow: %v, prev: %v, expected no change", flow.LayState.Alloc, flow.LayState.Prev)
+	}
+	for _, k := range flow.Kids {
+		if kb := k.(Node2D).AsWidget(); kb.LayState.AllocChanged() {
+			t.Errorf("unchanged flow item %v: %v, prev: %v, expected no change", kb.Nm, kb.LayState.Alloc, kb.LayState.Prev)
+		}
+	}
+}
+
+func TestWidgetBoxSpaceStateStyle(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	sl := AddNewSlider(outer, "slider")
+	vp.FullRender2DTree()
+	bs := sl.BoxSpace()
+	// e.g., a larger padding when hovered
+	sl.StateStyles[SliderHover].Layout.Padding.Dots = sl.StateStyles[SliderActive].Layout.Padding.Dots + 4
+	sl.SetSliderState(SliderHover)
+	if got := sl.BoxSpace(); got != bs+4 {
+		t.Errorf("box space after the state style: %v, expected %v", got, bs+4)
+	}
+}
+
+func TestLayoutIsLayoutStable(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "static", LayoutVert)
+	addTestBox(ly, "box", 50, 20)
+	vp.FullRender2DTree()
+	if !ly.IsLayoutStable() {
+		t.Errorf("static layout not stable after one pass")
+	}
+
+	// a percentage of the parent width is only known after the first pass --
+	// nested, as the top layout always fills the viewport
+	vp = testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "pct", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(200))
+	box := AddNewSpace(ly, "box")
+	box.SetProp("width", units.NewPct(50))
+	pass := 0
+	for ; pass < 5; pass++ {
+		vp.FullRender2DTree()
+		if ly.IsLayoutStable() {
+			break
+		}
+	}
+	if pass == 0 || pass == 5 {
+		t.Errorf("percentage layout stable after %d extra passes, expected to converge after at least 1", pass)
+	}
+	if sz := box.LayState.Alloc.Size.X; sz != 100 {
+		t.Errorf("converged width: %v, expected 100", sz)
+	}
+
+	// a wrapping flow is laid out in two iterations within each pass
+	vp = testViewport(200, 300)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "flow", LayoutHorizFlow)
+	for i := 0; i < 10; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 60, 20)
+	}
+	vp.FullRender2DTree()
+	vp.FullRender2DTree()
+	if !outer.IsLayoutStable() {
+		t.Errorf("static wrapping flow not stable after two passes")
+	}
+}
+
+func benchmarkRelayout(b *testing.B, full bool) {
+	vp := testDeepTree(4, 4)
+	vp.FullRender2DTree()
+	ly := vp.Child(0).(*Layout)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if full {
+			ly.ReRender2DTree()
+		} else {
+			ly.Relayout()
+		}
+	}
+}
+
+func BenchmarkLayoutRelayout(b *testing.B) {
+	benchmarkRelayout(b, false)
+}
+
+func BenchmarkLayoutReRender(b *testing.B) {
+	benchmarkRelayout(b, true)
+}
+
+// benchmarkAdds adds n rows to a rendered layout, either one at a time,
+// each within its own update (re-rendered via the viewport at each
+// UpdateEnd), or all within a single BulkAdd
+func benchmarkAdds(b *testing.B, n int, bulk bool) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		vp := testViewport(400, 300)
+		ly := AddNewLayout(vp, "list", LayoutVert)
+		vp.FullRender2DTree()
+		b.StartTimer()
+		if bulk {
+			ly.EnsureChildCapacity(n)
+			ly.BulkAdd(func() {
+				for j := 0; j < n; j++ {
+					addTestBox(ly, "row", 100, 20)
+				}
+			})
+			continue
+		}
+		for j := 0; j < n; j++ {
+			updt := ly.UpdateStart()
+			addTestBox(ly, "row", 100, 20)
+			ly.SetFullReRender()
+			ly.UpdateEnd(updt)
+		}
+	}
+}
+
+func BenchmarkLayoutAddIncremental(b *testing.B) {
+	benchmarkAdds(b, 1000, false)
+}
+
+func BenchmarkLayoutAddBulk(b *testing.B) {
+	benchmarkAdds(b, 1000, true)
+}
+
+func TestLayoutScrollBarsSig(t *testing.T) {
+	vp, ly := testScrollLayout(50, 50)
+	box := ly.Child(0).(*Space)
+	var sigs []bool
+	ly.ScrollBarsSig.Connect(vp.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if mat32.Dims(sig) == mat32.Y {
+			sigs = append(sigs, data.(bool))
+		}
+	})
+	render := func(h float32) {
+		box.SetFixedHeight(units.NewPx(h))
+		vp.FullRender2DTree()
+	}
+
+	render(50)
+	render(300)
+	if _, v := ly.ScrollBarsActive(); !v {
+		t.Errorf("expected V scroll active for overflowing content")
+	}
+	render(400)
+	render(50)
+	if _, v := ly.ScrollBarsActive(); v {
+		t.Errorf("expected V scroll inactive for fitting content")
+	}
+	render(60)
+	if len(sigs) != 2 || !sigs[0] || sigs[1] {
+		t.Errorf("scrollbar signals: %v, expected [true false]", sigs)
+	}
+}
+
+func TestLayoutFrStretch(t *testing.T) {
+	vp := testViewport(400, 100)
+	ly := AddNewLayout(vp, "row", LayoutHoriz)
+	ly.SetStretchMax()
+	var boxes [2]*Space
+	for i := range boxes {
+		boxes[i] = AddNewSpace(ly, "box")
+		boxes[i].SetProp("width", units.NewFr(float32(i+1)))
+		boxes[i].SetFixedHeight(units.NewPx(10))
+	}
+	vp.FullRender2DTree()
+
+	var ext [2]float32
+	for i, bx := range boxes {
+		if !bx.LayState.Size.HasMaxStretch(mat32.X) {
+			t.Errorf("fr box %d not stretchy", i)
+		}
+		ext[i] = bx.LayState.Alloc.Size.X - bx.LayState.Size.Pref.X
+	}
+	if ext[0] <= 0 || mat32.Abs(2*ext[0]-ext[1]) > 0.01 {
+		t.Errorf("fr extra space: %v, expected 1:2 split", ext)
+	}
+}
+
+func TestLayoutZeroAlloc(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "row", LayoutHoriz)
+	addTestBox(ly, "box1", 20, 10)
+	addTestBox(ly, "box2", 20, 10)
+	testSizeTree(vp)
+
+	ly.LayState.Alloc.Size = mat32.Vec2Zero
+	ly.Layout2D(vp.VpBBox, 0)
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		if !ni.LayState.Alloc.PosRel.IsNil() || !ni.LayState.Alloc.Size.IsNil() {
+			t.Errorf("zero alloc: child %d laid out at: %v size: %v", i, ni.LayState.Alloc.PosRel, ni.LayState.Alloc.Size)
+		}
+	}
+
+	ly.LayState.Alloc.Size = mat32.NewVec2(100, 50)
+	ly.Layout2D(vp.VpBBox, 0)
+	for i, k := range ly.Kids {
+		ni := k.(Node2D).AsWidget()
+		if ni.LayState.Alloc.PosRel.X != float32(i*20) || ni.LayState.Alloc.Size.X != 20 {
+			t.Errorf("child %d laid out at: %v size: %v", i, ni.LayState.Alloc.PosRel, ni.LayState.Alloc.Size)
+		}
+	}
+}
+
+func TestLayoutMinThumbSize(t *testing.T) {
+	vp, ly := testScrollLayout(50, 10000)
+	ly.SetProp("min-thumb-size", units.NewPx(20))
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	sc := ly.Scrolls[mat32.Y]
+	if sc.ThSize < 20 {
+		t.Errorf("thumb size: %v, expected at least 20", sc.ThSize)
+	}
+	sc.SetValue(sc.Max)
+	if sc.Value != sc.Max-sc.ThumbVal {
+		t.Errorf("max scroll value: %v, expected %v", sc.Value, sc.Max-sc.ThumbVal)
+	}
+	if mat32.Abs(sc.Pos+sc.ThSize-sc.Size) > 1 {
+		t.Errorf("thumb at max: pos %v + size %v, expected to reach end: %v", sc.Pos, sc.ThSize, sc.Size)
+	}
+	sc.SetSliderPos(sc.Size)
+	if sc.Value != sc.Max-sc.ThumbVal {
+		t.Errorf("value with thumb at end: %v, expected %v", sc.Value, sc.Max-sc.ThumbVal)
+	}
+}
+
+func TestLayoutScrollStep(t *testing.T) {
+	vp, ly := testScrollLayout(300, 300)
+	ly.SetProp("h-scroll-step", units.NewPx(5))
+	ly.SetProp("v-scroll-step", units.NewPx(30))
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.X] || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected both scrollbars, has: %v", ly.HasScroll)
+	}
+	hs, vs := ly.Scrolls[mat32.X], ly.Scrolls[mat32.Y]
+	if hs.Step != 5 || hs.PageStep != 50 {
+		t.Errorf("H step: %v page: %v, expected 5, 50", hs.Step, hs.PageStep)
+	}
+	if vs.Step != 30 || vs.PageStep != 300 {
+		t.Errorf("V step: %v page: %v, expected 30, 300", vs.Step, vs.PageStep)
+	}
+}
+
+func TestLayoutProfile(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	var inner [2]*Layout
+	for i := range inner {
+		inner[i] = AddNewLayout(outer, "inner", LayoutHoriz)
+		for j := 0; j < 10; j++ {
+			addTestBox(inner[i], "box", 20, 20)
+		}
+	}
+	LayoutProfile = true
+	defer func() { LayoutProfile = false }()
+	for frame := 0; frame < 2; frame++ {
+		vp.FullRender2DTree()
+		if LayoutProf.Size2D != 3 || LayoutProf.Layout2D != 3 {
+			t.Errorf("frame %d passes: Size2D %v Layout2D %v, expected 3 each", frame, LayoutProf.Size2D, LayoutProf.Layout2D)
+		}
+		for _, ly := range []*Layout{outer, inner[0], inner[1]} {
+			nd, ok := LayoutProf.Node(ly)
+			if !ok || nd.Size2D != 1 || nd.Layout2D != 1 || nd.Path != ly.Path() {
+				t.Errorf("frame %d stats for %v: %+v, expected 1 pass each", frame, ly.Path(), nd)
+			}
+		}
+		if sl := LayoutProf.Slowest(1); len(sl) != 1 || sl[0].Gather < 0 || sl[0].Gather > LayoutProf.Gather {
+			t.Errorf("frame %d slowest: %+v, total gather: %v", frame, sl, LayoutProf.Gather)
+		}
+	}
+	LayoutProfile = false
+	LayoutProf.Reset()
+	vp.FullRender2DTree()
+	if LayoutProf.Size2D != 0 || len(LayoutProf.Nodes) != 0 {
+		t.Errorf("stats accumulated with profiling off: %v nodes", len(LayoutProf.Nodes))
+	}
+}
+
+func TestLayoutScrollNoDrift(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scroll")
+	}
+	box := ly.Child(0).(Node2D).AsWidget()
+	orig := box.LayState.Alloc.PosOrig
+	if orig != box.LayState.Alloc.Pos {
+		t.Fatalf("unscrolled box pos: %v, expected PosOrig: %v", box.LayState.Alloc.Pos, orig)
+	}
+	sc := ly.Scrolls[mat32.Y]
+	for i, v := range []float32{40, 40, 80, 20} {
+		sc.SetValue(v)
+		ly.Move2DTree()
+		ly.Layout2DTree() // re-layout with the scroll in effect
+		sc.SetValue(v + 10)
+		ly.Move2DTree()
+		if box.LayState.Alloc.PosOrig != orig {
+			t.Errorf("scroll %d: PosOrig: %v, expected: %v", i, box.LayState.Alloc.PosOrig, orig)
+		}
+		exp := orig.Y - float32(LayoutRoundDots(v+10))
+		if box.LayState.Alloc.Pos.Y != exp {
+			t.Errorf("scroll %d to %v: box y: %v, expected %v", i, v+10, box.LayState.Alloc.Pos.Y, exp)
+		}
+	}
+}
+
+// testBaseBox is a fixed-size box that reports a text baseline
+type testBaseBox struct {
+	Space
+	Baseline float32
+}
+
+func (tb *testBaseBox) BaselineOffset() float32 {
+	return tb.Baseline
+}
+
+func TestLayoutBaselineSize(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "col", LayoutVert)
+	ly.BaselineSize = true
+	for i := 0; i < 3; i++ {
+		tb := &testBaseBox{Baseline: 9}
+		tb.InitName(tb, "box")
+		ly.AddChild(tb)
+		tb.SetFixedWidth(units.NewPx(20))
+		tb.SetFixedHeight(units.NewPx(12))
+	}
+	testSizeTree(vp)
+	// 3 * 12 = 36, less 9 above first baseline and 3 below last
+	if ly.LayState.Size.Need.Y != 24 {
+		t.Errorf("baseline sized height: %v, expected 24", ly.LayState.Size.Need.Y)
+	}
+	// the children are positioned from the first baseline, so the content
+	// fits the alloc without scrolling
+	vp.FullRender2DTree()
+	spc := ly.BoxSpace()
+	if ly.LayState.Alloc.Size.Y != 24+2*spc {
+		t.Errorf("baseline sized alloc: %v, expected %v", ly.LayState.Alloc.Size.Y, 24+2*spc)
+	}
+	if ly.ChildSize.Y+spc != ly.LayState.Alloc.Size.Y {
+		t.Errorf("baseline sized content: %v, alloc: %v", ly.ChildSize.Y+spc, ly.LayState.Alloc.Size.Y)
+	}
+	if ly.HasScroll[mat32.Y] {
+		t.Errorf("baseline sized layout has a vertical scrollbar")
+	}
+
+	ly.BaselineSize = false
+	testSizeTree(vp)
+	if ly.LayState.Size.Need.Y != 36 {
+		t.Errorf("regular height: %v, expected 36", ly.LayState.Size.Need.Y)
+	}
+}
+
+func TestGridItemsAlign(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	// a 20x20 box in a 100x60 cell
+	box := addTestBox(ly, "box", 20, 20)
+	tall := addTestBox(ly, "tall", 20, 60)
+	wide := addTestBox(ly, "wide", 100, 20)
+	addTestBox(ly, "wide2", 100, 20)
+	offs := func() (x, y float32) {
+		vp.FullRender2DTree()
+		return box.LayState.Alloc.PosRel.X - wide.LayState.Alloc.PosRel.X, box.LayState.Alloc.PosRel.Y - tall.LayState.Alloc.PosRel.Y
+	}
+
+	// default: the start horizontally
+	if x, _ := offs(); x != 0 {
+		t.Errorf("default x offset in cell: %v, expected 0", x)
+	}
+	// the items alignment of the grid
+	ly.SetProp("grid-justify-items", "center")
+	ly.SetProp("grid-align-items", GridItemsEnd)
+	if x, y := offs(); x != 40 || y != 40 {
+		t.Errorf("items offset in cell: %v, %v, expected 40, 40", x, y)
+	}
+	// the explicit alignment of the child, even if it is the start
+	box.SetProp("horizontal-align", gist.AlignLeft)
+	if x, y := offs(); x != 0 || y != 40 {
+		t.Errorf("explicit offset in cell: %v, %v, expected 0, 40", x, y)
+	}
+	box.DeleteProp("horizontal-align")
+	// the items alignment overrides the inherited alignment, until unset
+	ly.InheritAlign = true
+	ly.SetProp("horizontal-align", gist.AlignRight)
+	if x, _ := offs(); x != 40 {
+		t.Errorf("items over inherited x offset in cell: %v, expected 40", x)
+	}
+	ly.SetProp("grid-justify-items", "unset")
+	if x, _ := offs(); x != 80 || ly.GridJustifyItems != GridItemsUnset {
+		t.Errorf("unset items x offset in cell: %v, expected inherited 80", x)
+	}
+}
+
+func TestGridBaseline(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "form", LayoutGrid)
+	ly.SetProp("columns", 2)
+	// small and large text: baseline 9 in 12 high, and 24 in 30 high
+	var bbs []*testBaseBox
+	for i, sz := range [][2]float32{{12, 9}, {30, 24}} {
+		tb := &testBaseBox{Baseline: sz[1]}
+		tb.InitName(tb, fmt.Sprintf("cell%d", i))
+		ly.AddChild(tb)
+		tb.SetFixedWidth(units.NewPx(20))
+		tb.SetFixedHeight(units.NewPx(sz[0]))
+		tb.SetProp("vertical-align", gist.AlignBaseline)
+		bbs = append(bbs, tb)
+	}
+	vp.FullRender2DTree()
+
+	b0 := bbs[0].LayState.Alloc.PosRel.Y + bbs[0].Baseline
+	b1 := bbs[1].LayState.Alloc.PosRel.Y + bbs[1].Baseline
+	if b0 != b1 {
+		t.Errorf("baselines not aligned: %v vs %v", b0, b1)
+	}
+	if y := bbs[1].LayState.Alloc.PosRel.Y; y != 0 {
+		t.Errorf("large cell at: %v, expected at top of row", y)
+	}
+	// 24 above the baseline + 6 below, from the large cell
+	if rh := ly.GridData[Row][0].AllocSize; rh < 30 {
+		t.Errorf("row height: %v, expected at least 30", rh)
+	}
+}
+
+func TestLayoutOverflowFade(t *testing.T) {
+	for _, fade := range []bool{false, true} {
+		vp, ly := testScrollLayout(50, 300)
+		if fade {
+			ly.SetProp("overflow", gist.OverflowFade)
+		}
+		vp.FullRender2DTree()
+		if !ly.HasScroll[mat32.Y] {
+			t.Fatalf("fade: %v expected V scroll", fade)
+		}
+		if ly.ShowScrollBars() == fade {
+			t.Errorf("fade: %v show scrollbars: %v", fade, ly.ShowScrollBars())
+		}
+		if fade {
+			if ly.ExtraSize.X != 0 {
+				t.Errorf("OverflowFade extra size for scrollbar: %v", ly.ExtraSize)
+			}
+			if ly.ChildrenBBox2D() != ly.ChildrenBBox2DWidget() {
+				t.Errorf("OverflowFade children bbox excludes scrollbar region")
+			}
+			if sb := ly.Scrolls[mat32.Y].VpBBox; sb != image.ZR {
+				t.Errorf("OverflowFade hidden scrollbar laid out at: %v", sb)
+			}
+			ly.ScrollDelta(&mouse.ScrollEvent{Delta: image.Pt(0, 20)})
+			if ly.Scrolls[mat32.Y].Value <= 0 {
+				t.Errorf("OverflowFade did not scroll: %v", ly.Scrolls[mat32.Y].Value)
+			}
+		}
+	}
+}
+
+func TestLayoutInsertChildAt(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "col", LayoutVert)
+	for i := 0; i < 3; i++ {
+		addTestBox(ly, "box", 20, 10)
+	}
+	vp.FullRender2DTree()
+	var prv []float32
+	for _, k := range ly.Kids {
+		prv = append(prv, k.(Node2D).AsWidget().LayState.Alloc.PosRel.Y)
+	}
+
+	nw := &Space{}
+	nw.InitName(nw, "new")
+	nw.SetFixedWidth(units.NewPx(20))
+	nw.SetFixedHeight(units.NewPx(15))
+	if err := ly.InsertChildAt(1, nw); err != nil {
+		t.Fatal(err)
+	}
+	vp.FullRender2DTree()
+	if ly.Child(1) != nw.This() {
+		t.Fatalf("child not inserted at index 1")
+	}
+	if y := ly.Child(0).(Node2D).AsWidget().LayState.Alloc.PosRel.Y; y != prv[0] {
+		t.Errorf("earlier child moved: %v, expected %v", y, prv[0])
+	}
+	for i := 1; i < 3; i++ {
+		y := ly.Child(i + 1).(Node2D).AsWidget().LayState.Alloc.PosRel.Y
+		if y != prv[i]+15 {
+			t.Errorf("later child %d pos: %v, expected shifted to %v", i, y, prv[i]+15)
+		}
+	}
+
+	// the scroll position is kept for a scrolled list
+	vp, sl := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	sl.Scrolls[mat32.Y].SetValue(50)
+	ins := &Space{}
+	ins.InitName(ins, "ins")
+	ins.SetFixedWidth(units.NewPx(20))
+	ins.SetFixedHeight(units.NewPx(15))
+	if err := sl.InsertChildAt(0, ins); err != nil {
+		t.Fatal(err)
+	}
+	vp.FullRender2DTree()
+	if v := sl.Scrolls[mat32.Y].Value; v != 50 {
+		t.Errorf("scroll value after insert: %v, expected 50", v)
+	}
+
+	st := AddNewLayout(outer, "stack", LayoutStacked)
+	addTestBox(st, "p0", 20, 10)
+	addTestBox(st, "p1", 20, 10)
+	st.StackTop = 1
+	top := st.Child(1)
+	p := &Space{}
+	p.InitName(p, "pnew")
+	st.InsertChildAt(0, p)
+	if st.Child(st.StackTop) != top {
+		t.Errorf("stack top not preserved: %v", st.StackTop)
+	}
+}
+
+// testPaintBox is a Space that records the order in which it is rendered
+type testPaintBox struct {
+	Space
+	Order *[]string
+}
+
+func (pb *testPaintBox) Render2D() {
+	*pb.Order = append(*pb.Order, pb.Nm)
+	pb.Space.Render2D()
+}
+
+func TestLayoutRaiseToTop(t *testing.T) {
+	vp := testViewport(200, 200)
+	fr := AddNewFrame(vp, "canvas", LayoutNil)
+	var order []string
+	var boxes []*testPaintBox
+	for _, nm := range []string{"a", "b", "c"} {
+		pb := &testPaintBox{Order: &order}
+		pb.InitName(pb, nm)
+		fr.AddChild(pb)
+		boxes = append(boxes, pb)
+	}
+	vp.FullRender2DTree()
+
+	if err := fr.RaiseToTop(boxes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if fr.NeedsFullReRender() {
+		t.Errorf("z-order change in nil layout should not need a re-layout")
+	}
+	order = nil
+	fr.Render2DChildren()
+	if exp := "b c a"; strings.Join(order, " ") != exp {
+		t.Errorf("paint order: %v, expected %v", order, exp)
+	}
+	if !PaintsAfter(boxes[0], boxes[2]) {
+		t.Errorf("raised child should be hit before the previous top child")
+	}
+	po := PaintOrder{}
+	if idx := po.Index(boxes[1]); idx != 0 || len(po) != len(boxes) {
+		t.Errorf("paint order index: %v, recorded: %v, expected 0 with all siblings recorded", idx, len(po))
+	}
+	if po.Index(boxes[0]) != 2 || !po.PaintsAfter(boxes[0], boxes[2]) {
+		t.Errorf("recorded paint order does not match PaintsAfter")
+	}
+
+	fr.LowerChild(boxes[0])
+	fr.LowerToBottom(boxes[2])
+	order = nil
+	fr.Render2DChildren()
+	if exp := "c b a"; strings.Join(order, " ") != exp {
+		t.Errorf("paint order: %v, expected %v", order, exp)
+	}
+	if err := fr.RaiseChild(vp); err == nil {
+		t.Errorf("expected error raising a non-child")
+	}
+}
+
+func TestLayoutAspectMaxHeight(t *testing.T) {
+	vp := testViewport(400, 300)
+	ly := AddNewLayout(vp, "col", LayoutVert)
+	ly.SetStretchMax()
+	tile := AddNewSpace(ly, "tile")
+	tile.SetProp("width", units.NewPx(50))
+	tile.SetProp("height", units.NewPx(150))
+	tile.SetStretchMaxWidth()
+	tile.SetProp("max-height", units.NewPx(100))
+	tile.SetProp("aspect-ratio", 2)
+	vp.FullRender2DTree()
+
+	// full width would need a height of ~200 -- width is reduced instead
+	if sz := tile.LayState.Alloc.Size; sz != mat32.NewVec2(200, 100) {
+		t.Errorf("aspect tile size: %v, expected (200, 100) within max-height", sz)
+	}
+
+	tile.SetProp("aspect-ratio", 8)
+	vp.FullRender2DTree()
+	sz := tile.LayState.Alloc.Size
+	if sz.X <= 200 || sz.Y > 100 || mat32.Abs(sz.X/sz.Y-8) > 0.001 {
+		t.Errorf("aspect tile size: %v, expected full width at ratio 8", sz)
+	}
+
+	// the height is also kept within the cell allocated by the layout
+	vp = testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	row := AddNewLayout(outer, "row", LayoutHoriz)
+	row.SetFixedHeight(units.NewPx(50))
+	row.SetStretchMaxWidth()
+	tile = AddNewSpace(row, "tile")
+	tile.SetProp("width", units.NewPx(50))
+	tile.SetProp("height", units.NewPx(20))
+	tile.SetStretchMax()
+	tile.SetProp("aspect-ratio", 2)
+	vp.FullRender2DTree()
+	if sz := tile.LayState.Alloc.Size; sz != mat32.NewVec2(100, 50) {
+		t.Errorf("aspect tile size: %v, expected (100, 50) within its cell", sz)
+	}
+
+	// without a height, the height comes from the ratio -- the cell of a
+	// vertical layout is not a constraint, and the layout makes room for it
+	vp = testViewport(400, 300)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "col", LayoutVert)
+	ly.SetFixedWidth(units.NewPx(400))
+	tile = AddNewSpace(ly, "tile")
+	tile.SetStretchMaxWidth()
+	tile.SetProp("max-height", units.NewPx(100))
+	tile.SetProp("aspect-ratio", 2)
+	below := addTestBox(ly, "below", 20, 20)
+	vp.FullRender2DTree()
+	spc := ly.BoxSpace()
+	if sz := tile.LayState.Alloc.Size; sz != mat32.NewVec2(200, 100) {
+		t.Errorf("aspect tile size: %v, expected (200, 100) from the ratio", sz)
+	}
+	if y := below.LayState.Alloc.PosRel.Y; y < spc+100 {
+		t.Errorf("next child pos: %v, expected below the tile", y)
+	}
+	if ly.HasScroll[mat32.Y] {
+		t.Errorf("aspect tile layout has a vertical scrollbar")
+	}
+}
+
+func TestFrameClipToRadius(t *testing.T) {
+	vp := testViewport(200, 200)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	fr := AddNewFrame(outer, "frame", LayoutVert)
+	fr.SetFixedWidth(units.NewPx(100))
+	fr.SetFixedHeight(units.NewPx(100))
+	fr.SetProp("border-radius", units.NewPx(20))
+	fr.SetProp("border-width", 0)
+	fr.SetProp("margin", 0)
+	fr.SetProp("padding", 0)
+	fr.SetProp("background-color", "white")
+	fill := AddNewFrame(fr, "fill", LayoutVert)
+	fill.SetFixedWidth(units.NewPx(100))
+	fill.SetFixedHeight(units.NewPx(100))
+	fill.SetProp("border-width", 0)
+	fill.SetProp("margin", 0)
+	fill.SetProp("background-color", "red")
+
+	red := color.RGBA{255, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	for _, clip := range []bool{false, true} {
+		fr.ClipToRadius = clip
+		vp.FullRender2DTree()
+		draw.Draw(vp.Pixels, vp.Pixels.Bounds(), image.NewUniform(white), image.ZP, draw.Src)
+		testRender2D(vp)
+		pos, sz := fr.BgBox()
+		crn := LayoutRoundPoint(pos).Add(image.Pt(1, 1))
+		ctr := LayoutRoundPoint(pos.Add(sz.MulScalar(0.5)))
+		if c := vp.Pixels.RGBAAt(ctr.X, ctr.Y); c != red {
+			t.Errorf("clip: %v center color: %v, expected child fill", clip, c)
+		}
+		if c := vp.Pixels.RGBAAt(crn.X, crn.Y); (c == red) == clip {
+			t.Errorf("clip: %v corner color: %v, expected child fill clipped only with ClipToRadius", clip, c)
+		}
+		if !clip {
+			continue
+		}
+		// a pixel that the arc of the corner crosses is partly covered, and
+		// blended with the background
+		edg := LayoutRoundPoint(pos).Add(image.Pt(3, 8))
+		if c := vp.Pixels.RGBAAt(edg.X, edg.Y); c == red || c == white {
+			t.Errorf("corner edge color: %v, expected antialiased blend", c)
+		}
+	}
+}
+
+func TestDebugLayoutBounds(t *testing.T) {
+	vp, ly := testScrollLayout(50, 50)
+	box := ly.Child(0).(*Space)
+	box.SetProp("margin", units.NewPx(5))
+	vp.FullRender2DTree()
+	testRender2D(vp)
+	corner := LayoutRoundPoint(box.LayState.Alloc.Pos)
+	inner := corner.Add(image.Pt(5, 5))
+	cr0, in0 := vp.Pixels.RGBAAt(corner.X, corner.Y), vp.Pixels.RGBAAt(inner.X, inner.Y)
+
+	DebugLayoutBounds = true
+	defer func() { DebugLayoutBounds = false }()
+	vp.FullRender2DTree()
+	testRender2D(vp)
+	if c := vp.Pixels.RGBAAt(corner.X, corner.Y); c == cr0 {
+		t.Errorf("alloc box corner color: %v, expected outline drawn", c)
+	}
+	if c := vp.Pixels.RGBAAt(inner.X, inner.Y); c == in0 {
+		t.Errorf("margin box corner color: %v, expected outline drawn", c)
+	}
+}
+
+func TestFrameShadowNotClipped(t *testing.T) {
+	vp := testViewport(200, 200)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	fr := AddNewFrame(outer, "frame", LayoutVert)
+	fr.SetFixedWidth(units.NewPx(60))
+	fr.SetFixedHeight(units.NewPx(60))
+	fr.SetProp("margin", units.NewPx(2))
+	fr.SetProp("background-color", "white")
+	fr.SetProp("box-shadow.h-offset", units.NewPx(10))
+	fr.SetProp("box-shadow.v-offset", units.NewPx(10))
+	fr.SetProp("box-shadow.color", "blue")
+	vp.FullRender2DTree()
+	testRender2D(vp)
+
+	sb := fr.ShadowVpBBox
+	if sb.In(fr.VpBBox) {
+		t.Fatalf("shadow: %v expected to extend beyond frame: %v", sb, fr.VpBBox)
+	}
+	// inside the shadow, but outside of the frame bounds
+	pt := sb.Max.Sub(image.Pt(2, 2))
+	if pt.In(fr.VpBBox) {
+		t.Fatalf("test point: %v should be outside of frame: %v", pt, fr.VpBBox)
+	}
+	blue := color.RGBA{0, 0, 255, 255}
+	if c := vp.Pixels.RGBAAt(pt.X, pt.Y); c != blue {
+		t.Errorf("color outside frame bounds: %v, expected shadow drawn", c)
+	}
+	// background drawn over the shadow
+	bp, bsz := fr.BgBox()
+	ctr := LayoutRoundPoint(bp.Add(bsz.MulScalar(0.5)))
+	if c := vp.Pixels.RGBAAt(ctr.X, ctr.Y); c == blue {
+		t.Errorf("frame center color: %v, expected background over shadow", c)
+	}
+	// re-rendering just the frame uploads its shadow too
+	vbb, wbb := ReRender2DBBoxes(fr)
+	if !sb.In(vbb) || !fr.VpBBox.In(vbb) {
+		t.Errorf("re-render region: %v, expected to cover frame: %v and shadow: %v", vbb, fr.VpBBox, sb)
+	}
+	if off, exp := wbb.Min.Sub(vbb.Min), fr.WinBBox.Min.Sub(fr.VpBBox.Min); off != exp || wbb.Size() != vbb.Size() {
+		t.Errorf("re-render window region: %v, expected region: %v offset by %v", wbb, vbb, exp)
+	}
+}
+
+func TestLayoutShiftWheel(t *testing.T) {
+	vp, ly := testScrollLayout(300, 300)
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.X] || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected both scrollbars: %v", ly.HasScroll)
+	}
+	me := &mouse.ScrollEvent{Delta: image.Pt(0, 20)}
+	me.SetModifiers(key.Shift)
+	ly.ScrollDelta(me)
+	if x := ly.Scrolls[mat32.X].Value; x != 20 {
+		t.Errorf("shift wheel horizontal scroll: %v, expected 20", x)
+	}
+	if y := ly.Scrolls[mat32.Y].Value; y != 0 {
+		t.Errorf("shift wheel vertical scroll: %v, expected unchanged 0", y)
+	}
+	if !me.IsProcessed() {
+		t.Errorf("shift wheel event not processed")
+	}
+
+	// no horizontal scrollbar: left for a parent
+	vp, ly = testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	me = &mouse.ScrollEvent{Delta: image.Pt(0, 20)}
+	me.SetModifiers(key.Shift)
+	ly.ScrollDelta(me)
+	if y := ly.Scrolls[mat32.Y].Value; y != 0 || me.IsProcessed() {
+		t.Errorf("shift wheel without horizontal scroll: %v processed: %v, expected unchanged", y, me.IsProcessed())
+	}
+}
+
+func TestLayoutScrollByPixels(t *testing.T) {
+	vp, ly := testScrollLayout(50, 300)
+	vp.FullRender2DTree()
+	for i := 0; i < 10; i++ {
+		ly.ScrollByPixels(mat32.NewVec2(0, 0.25))
+	}
+	if y := ly.Scrolls[mat32.Y].Value; y != 2.5 {
+		t.Errorf("fractional scroll: %v, expected accumulated 2.5", y)
+	}
+
+	// precise events, with the horizontal remainder left for a parent
+	for i := 0; i < 4; i++ {
+		me := &mouse.ScrollEvent{Precise: true, PreciseDelta: mat32.NewVec2(0.5, 0.75)}
+		ly.ScrollDelta(me)
+		if me.IsProcessed() || me.PreciseDelta != mat32.NewVec2(0.5, 0) {
+			t.Errorf("precise event remainder: %v processed: %v, expected only horizontal left", me.PreciseDelta, me.IsProcessed())
+		}
+	}
+	if y := ly.Scrolls[mat32.Y].Value; y != 5.5 {
+		t.Errorf("precise scroll: %v, expected accumulated 5.5", y)
+	}
+	me := &mouse.ScrollEvent{Precise: true, PreciseDelta: mat32.NewVec2(0, 1000)}
+	ly.ScrollDelta(me)
+	if y, mx := ly.Scrolls[mat32.Y].Value, ly.Scrolls[mat32.Y].Max-ly.Scrolls[mat32.Y].ThumbVal; y != mx || !me.IsProcessed() {
+		t.Errorf("precise scroll: %v, expected clamped to %v", y, mx)
+	}
+}
+
+func TestLayoutOrder(t *testing.T) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "row", LayoutHoriz)
+	var boxes []*Space
+	for i, ord := range []int{2, 0, 1} {
+		bx := addTestBox(ly, fmt.Sprintf("box%d", i), 20, 10)
+		bx.SetProp("order", ord)
+		boxes = append(boxes, bx)
+	}
+	vp.FullRender2DTree()
+
+	for i, exp := range []float32{40, 0, 20} {
+		if x := boxes[i].LayState.Alloc.PosRel.X; x != exp {
+			t.Errorf("child %d pos: %v, expected %v", i, x, exp)
+		}
+	}
+	for i, k := range ly.Kids {
+		if k != boxes[i] {
+			t.Errorf("order should not change the children: child %d is %v", i, k.Name())
+		}
+	}
+
+	vp = testViewport(200, 100)
+	rly := AddNewLayout(vp, "paint", LayoutHoriz)
+	var paint []string
+	for i, nm := range []string{"a", "b", "c"} {
+		pb := &testPaintBox{Order: &paint}
+		pb.InitName(pb, nm)
+		pb.SetProp("order", []int{2, 0, 1}[i])
+		rly.AddChild(pb)
+	}
+	vp.FullRender2DTree()
+	paint = nil
+	rly.Render2DChildren()
+	if exp := "b c a"; strings.Join(paint, " ") != exp {
+		t.Errorf("paint order: %v, expected %v", paint, exp)
+	}
+
+	// order only applies to row and column layouts
+	rly.SetLayout(LayoutGrid)
+	paint = nil
+	rly.Render2DChildren()
+	if exp := "a b c"; strings.Join(paint, " ") != exp {
+		t.Errorf("grid paint order: %v, expected tree order %v", paint, exp)
+	}
+}
+
+func TestLayoutBorder(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "border", LayoutBorder)
+	ly.SetFixedWidth(units.NewPx(300))
+	ly.SetFixedHeight(units.NewPx(200))
+	north := addTestBox(ly, "north", 100, 20)
+	north.SetProp("region", "north")
+	south := addTestBox(ly, "south", 100, 30)
+	south.SetProp("region", gist.RegionSouth)
+	west := addTestBox(ly, "west", 40, 60)
+	west.SetProp("region", "west")
+	center := AddNewLayout(ly, "center", LayoutVert)
+	center.SetProp("width", units.NewPx(10))
+	center.SetProp("height", units.NewPx(10))
+	center.SetStretchMax()
+	addTestBox(center, "content", 400, 400)
+	east := addTestBox(ly, "east", 50, 60)
+	east.SetProp("region", "east")
+	vp.FullRender2DTree()
+
+	tests := []struct {
+		ni        *WidgetBase
+		pos, size mat32.Vec2
+	}{
+		{north.AsWidget(), mat32.NewVec2(0, 0), mat32.NewVec2(300, 20)},
+		{south.AsWidget(), mat32.NewVec2(0, 170), mat32.NewVec2(300, 30)},
+		{west.AsWidget(), mat32.NewVec2(0, 20), mat32.NewVec2(40, 150)},
+		{east.AsWidget(), mat32.NewVec2(250, 20), mat32.NewVec2(50, 150)},
+		{center.AsWidget(), mat32.NewVec2(40, 20), mat32.NewVec2(210, 150)},
+	}
+	for _, ts := range tests {
+		alc := ts.ni.LayState.Alloc
+		if alc.PosRel != ts.pos || alc.Size != ts.size {
+			t.Errorf("%v alloc pos: %v size: %v, expected pos: %v size: %v", ts.ni.Nm, alc.PosRel, alc.Size, ts.pos, ts.size)
+		}
+	}
+	if !center.HasScroll[mat32.Y] || !center.HasScroll[mat32.X] {
+		t.Errorf("center should scroll, has: %v", center.HasScroll)
+	}
+	if ly.HasScroll[mat32.X] || ly.HasScroll[mat32.Y] {
+		t.Errorf("border layout itself should not scroll, has: %v", ly.HasScroll)
+	}
+
+	// unconstrained: edges stacked around the row of west, center and east
+	ly.SetProp("width", units.NewPx(0))
+	ly.SetProp("height", units.NewPx(0))
+	ly.SetProp("min-width", units.NewPx(0))
+	ly.SetProp("min-height", units.NewPx(0))
+	vp.FullRender2DTree()
+	if need := ly.LayState.Size.Need; need != mat32.NewVec2(100, 110) {
+		t.Errorf("border need: %v, expected (100, 110)", need)
+	}
+
+	// spacing only between the stacked items
+	ly.SetProp("spacing", units.NewPx(10))
+	center.SetProp("display", "none")
+	vp.FullRender2DTree()
+	if need := ly.LayState.Size.Need; need != mat32.NewVec2(100, 130) {
+		t.Errorf("border need with spacing: %v, expected (100, 130)", need)
+	}
+	west.SetProp("display", "none")
+	east.SetProp("display", "none")
+	vp.FullRender2DTree()
+	if need := ly.LayState.Size.Need; need != mat32.NewVec2(100, 60) {
+		t.Errorf("border need with only north and south: %v, expected (100, 60)", need)
+	}
+}
+
+func TestLayoutChildMargin(t *testing.T) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "row", LayoutHoriz)
+	first := addTestBox(ly, "first", 20, 10)
+	mid := addTestBox(ly, "mid", 20, 10)
+	mid.SetProp("margin", units.NewPx(10))
+	last := addTestBox(ly, "last", 20, 10)
+	vp.FullRender2DTree()
+
+	if sz := mid.LayState.Alloc.Size; sz != mat32.NewVec2(40, 30) {
+		t.Errorf("margined child alloc size: %v, expected size plus margins (40, 30)", sz)
+	}
+	if x := last.LayState.Alloc.PosRel.X; x != 60 {
+		t.Errorf("next sibling pos: %v, expected 60 after trailing margin", x)
+	}
+	if off := mid.ChildrenBBox2D().Min.X - first.VpBBox.Max.X; off != 10 {
+		t.Errorf("margined child box offset from neighbor: %v, expected 10", off)
+	}
+	if need := ly.LayState.Size.Need; need != mat32.NewVec2(80, 30) {
+		t.Errorf("layout need: %v, expected to include margins (80, 30)", need)
+	}
+
+	// widgets sized from their content keep the max size of their style
+	sub := AddNewLayout(ly, "sub", LayoutVert)
+	sub.SetProp("margin", units.NewPx(5))
+	sub.SetProp("max-width", units.NewPx(50))
+	vp.FullRender2DTree()
+	if mx := sub.LayState.Size.Max.X; mx != 50 {
+		t.Errorf("layout child max: %v, expected style max 50", mx)
+	}
+}
+
+func TestGridAutoFit(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "cards", LayoutGrid)
+	ly.SetProp("auto-fit-min-width", units.NewPx(50))
+	ly.SetFixedWidth(units.NewPx(200))
+	for i := 0; i < 6; i++ {
+		addTestBox(ly, fmt.Sprintf("card%d", i), 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ly.GridSize.X != 4 || ly.GridSize.Y != 2 {
+		t.Errorf("grid size at 200 wide: %v, expected 4 x 2", ly.GridSize)
+	}
+
+	ly.SetFixedWidth(units.NewPx(120))
+	vp.FullRender2DTree()
+	if ly.GridSize.X != 2 || ly.GridSize.Y != 3 {
+		t.Errorf("grid size at 120 wide: %v, expected 2 x 3", ly.GridSize)
+	}
+	for i, gd := range ly.GridData[Col] {
+		if gd.AllocSize != 60 {
+			t.Errorf("col %d size: %v, expected stretched to 60", i, gd.AllocSize)
+		}
+	}
+	if x := ly.Child(1).(Node2D).AsWidget().LayState.Alloc.PosRel.X; x != 60 {
+		t.Errorf("2nd card pos: %v, expected in 2nd column at 60", x)
+	}
+	// the height follows the new number of rows in the same render
+	vp = testViewport(600, 600)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "cards", LayoutGrid)
+	ly.SetProp("auto-fit-min-width", units.NewPx(100))
+	ly.SetProp("spacing", units.NewPx(0))
+	ly.SetFixedWidth(units.NewPx(400))
+	for i := 0; i < 8; i++ {
+		addTestBox(ly, fmt.Sprintf("card%d", i), 50, 50)
+	}
+	vp.FullRender2DTree()
+	ly.SetFixedWidth(units.NewPx(200))
+	vp.FullRender2DTree()
+	spc := ly.BoxSpace()
+	if ly.GridSize.X != 2 || ly.LayState.Alloc.Size.Y != 200+2*spc {
+		t.Errorf("grid at 200 wide: %v, height: %v, expected 2 columns, height %v", ly.GridSize, ly.LayState.Alloc.Size.Y, 200+2*spc)
+	}
+	if ly.HasScroll[mat32.X] || ly.HasScroll[mat32.Y] {
+		t.Errorf("grid at 200 wide has scrollbars: %v", ly.HasScroll)
+	}
+}
+
+func TestLayoutChildAtPoint(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "overlap", LayoutBorder) // center children overlap
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	top := addTestBox(ly, "top", 20, 20)
+	top.SetProp("z-index", 1)
+	under := addTestBox(ly, "under", 20, 20)
+	vp.FullRender2DTree()
+
+	pt := ly.WinBBox.Min.Add(image.Point{50, 50})
+	if hit := ly.ChildAtPoint(pt); hit == nil || hit.Name() != "top" {
+		t.Errorf("hit: %v, expected higher z-index child: top", hit)
+	}
+	if !PaintsAfter(top, under) {
+		t.Errorf("higher z-index child should be painted after the other")
+	}
+	if kids := ly.PaintKids(); kids[1] != top {
+		t.Errorf("paint order: %v, expected top last", kids)
+	}
+
+	top.SetProp("z-index", 0)
+	vp.FullRender2DTree()
+	if hit := ly.ChildAtPoint(pt); hit == nil || hit.Name() != "under" {
+		t.Errorf("hit: %v, expected later child in tree order: under", hit)
+	}
+	if hit := ly.ChildAtPoint(ly.WinBBox.Max.Add(image.Point{10, 10})); hit != nil {
+		t.Errorf("hit outside: %v, expected nil", hit.Name())
+	}
+}
+
+func TestLayoutReverseRenderOrder(t *testing.T) {
+	vp := testViewport(200, 200)
+	ly := AddNewLayout(vp, "overlap", LayoutBorder) // center children overlap
+	ly.SetFixedWidth(units.NewPx(100))
+	ly.SetFixedHeight(units.NewPx(100))
+	var kids []*Frame
+	for _, clr := range []string{"red", "blue"} {
+		fr := AddNewFrame(ly, clr, LayoutVert)
+		fr.SetFixedWidth(units.NewPx(40))
+		fr.SetFixedHeight(units.NewPx(40))
+		fr.SetProp("background-color", clr)
+		kids = append(kids, fr)
+	}
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	for _, rev := range []bool{false, true} {
+		ly.ReverseRenderOrder = rev
+		vp.FullRender2DTree()
+		testRender2D(vp)
+		top, exp := kids[1], blue
+		if rev {
+			top, exp = kids[0], red
+		}
+		ctr := ly.VpBBox.Min.Add(ly.VpBBox.Size().Div(2))
+		if c := vp.Pixels.RGBAAt(ctr.X, ctr.Y); c != exp {
+			t.Errorf("reversed: %v center color: %v, expected: %v", rev, c, exp)
+		}
+		if kids := ly.PaintKids(); kids[1] != top {
+			t.Errorf("reversed: %v paint order: %v, expected %v last", rev, kids, top.Name())
+		}
+		if hit := ly.ChildAtPoint(ly.WinBBox.Min.Add(image.Point{50, 50})); hit == nil || hit.Name() != top.Name() {
+			t.Errorf("reversed: %v hit: %v, expected %v", rev, hit, top.Name())
+		}
+	}
+}
+
+func TestGridColumnsRange(t *testing.T) {
+	vp := testViewport(600, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "cards", LayoutGrid)
+	ly.SetProp("auto-fit-min-width", units.NewPx(50))
+	ly.SetProp("max-columns", 4)
+	ly.SetFixedWidth(units.NewPx(400))
+	for i := 0; i < 8; i++ {
+		addTestBox(ly, fmt.Sprintf("card%d", i), 20, 20)
+	}
+	vp.FullRender2DTree()
+	if ly.GridSize.X != 4 {
+		t.Errorf("columns at 400 wide: %v, expected 8 clamped to max 4", ly.GridSize.X)
+	}
+	if sz := ly.GridData[Col][0].AllocSize; sz != 100 {
+		t.Errorf("col size: %v, expected stretched to 100", sz)
+	}
+
+	ly.SetProp("min-columns", 2)
+	ly.SetFixedWidth(units.NewPx(60))
+	vp.FullRender2DTree()
+	if ly.GridSize.X != 2 {
+		t.Errorf("columns at 60 wide: %v, expected 1 raised to min 2", ly.GridSize.X)
+	}
+}
+
+func TestGridSetChildGridPos(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("columns", 3)
+	for i := 0; i < 4; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 20, 20)
+	}
+	box := addTestBox(ly, "placed", 20, 20)
+	if err := ly.SetChildGridPos(box, 1, 2, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ly.SetChildGridPos(box, -1, 2, 1, 1); err == nil {
+		t.Errorf("expected error for negative row")
+	}
+	if err := ly.SetChildGridPos(box, 1, 2, 0, 1); err == nil {
+		t.Errorf("expected error for zero span")
+	}
+	if err := ly.SetChildGridPos(outer, 1, 2, 1, 1); err == nil {
+		t.Errorf("expected error for non-child")
+	}
+	vp.FullRender2DTree()
+	lst := box.Sty.Layout
+	if lst.Row != 1 || lst.Col != 2 || lst.RowSpan != 2 || lst.ColSpan != 1 {
+		t.Errorf("grid pos: %v, %v span: %v, %v, expected 1, 2 span 2, 1", lst.Row, lst.Col, lst.RowSpan, lst.ColSpan)
+	}
+	if ly.GridSize.Y != 3 {
+		t.Errorf("grid rows: %v, expected 3 for the row span", ly.GridSize.Y)
+	}
+	pos := box.LayState.Alloc.PosRel
+	if x := ly.GridData[Col][2].AllocPosRel; pos.X != x {
+		t.Errorf("placed x: %v, expected at column 2: %v", pos.X, x)
+	}
+	if y := ly.GridData[Row][1].AllocPosRel; pos.Y < y {
+		t.Errorf("placed y: %v, expected in row 1 starting at: %v", pos.Y, y)
+	}
+	// the first row and col are placed exactly, not auto-placed
+	k3 := ly.Child(3).(Node2D)
+	if err := ly.SetChildGridPos(k3, 0, 0, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	vp.FullRender2DTree()
+	if at := ly.ChildAtGridPos(0, 0); at != k3 {
+		t.Errorf("child at 0, 0: %v, expected %v", at, k3.Name())
+	}
+}
+
+func TestGridChildAtGridPos(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("columns", 3)
+	ly.SetProp("rows", 2)
+	// box0 | big  big
+	// box1 | big  big
+	box0 := addTestBox(ly, "box0", 20, 20)
+	big := addTestBox(ly, "big", 40, 40)
+	big.SetProp("row-span", 2)
+	big.SetProp("col-span", 2)
+	box1 := addTestBox(ly, "box1", 20, 20)
+	vp.FullRender2DTree()
+
+	for _, cell := range []image.Point{{1, 0}, {2, 0}, {1, 1}, {2, 1}} {
+		if got := ly.ChildAtGridPos(cell.Y, cell.X); got != big.This().(Node2D) {
+			t.Errorf("cell row: %v col: %v: %v, expected spanning child: big", cell.Y, cell.X, got)
+		}
+	}
+	if got := ly.ChildAtGridPos(0, 0); got != box0.This().(Node2D) {
+		t.Errorf("cell 0, 0: %v, expected box0", got)
+	}
+	if got := ly.ChildAtGridPos(1, 0); got != box1.This().(Node2D) {
+		t.Errorf("cell 1, 0: %v, expected box1", got)
+	}
+	if got := ly.ChildAtGridPos(2, 0); got != nil {
+		t.Errorf("cell out of range: %v, expected nil", got)
+	}
+}
+
+func TestGridColumnLines(t *testing.T) {
+	for _, cols := range []int{3, 4} {
+		vp := testViewport(400, 300)
+		outer := AddNewLayout(vp, "outer", LayoutVert)
+		ly := AddNewLayout(outer, "grid", LayoutGrid)
+		ly.SetProp("columns", cols)
+		hdr := addTestBox(ly, "header", 20, 10)
+		hdr.SetProp("grid-column", "1 / -1")
+		for i := 0; i < 6; i++ {
+			addTestBox(ly, fmt.Sprintf("box%d", i), 20, 20)
+		}
+		vp.FullRender2DTree()
+
+		if ly.GridSize.X != cols {
+			t.Fatalf("grid cols: %v, expected %v", ly.GridSize.X, cols)
+		}
+		for c := 0; c < cols; c++ {
+			if got := ly.ChildAtGridPos(0, c); got != hdr.This().(Node2D) {
+				t.Errorf("%d cols: cell 0, %d: %v, expected header spanning all columns", cols, c, got)
+			}
+		}
+		// the boxes follow the header in the next rows
+		if got := ly.ChildAtGridPos(1, 0); got == nil || got.Name() != "box0" {
+			t.Errorf("%d cols: cell 1, 0: %v, expected box0", cols, got)
+		}
+		if got := ly.ChildAtGridPos(2, 5-cols); got == nil || got.Name() != "box5" {
+			t.Errorf("%d cols: cell 2, %d: %v, expected box5", cols, 5-cols, got)
+		}
+	}
+
+	if idx, span := GridLinePlace(2, -1, 0, 1, 4); idx != 1 || span != 3 {
+		t.Errorf("lines 2 / -1 of 4: %v, %v, expected 1, 3", idx, span)
+	}
+	if idx, span := GridLinePlace(-2, 0, 0, 1, 4); idx != 3 || span != 1 {
+		t.Errorf("line -2 of 4: %v, %v, expected 3, 1", idx, span)
+	}
+}
+
+func TestGridAutoPlaceSkipsSpans(t *testing.T) {
+	// explicit area spanning cols 0-1 of row 0, added after the auto children
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("grid-template-areas", "wide wide . .")
+	for i := 0; i < 4; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 20, 20)
+	}
+	wide := addTestBox(ly, "wide", 30, 20)
+	wide.SetProp("grid-area", "wide")
+	vp.FullRender2DTree()
+	if n := len(ly.GridData[Col]); n != 4 {
+		t.Fatalf("cols: %v, expected the 4 of the template", n)
+	}
+
+	exp := []image.Point{{2, 0}, {3, 0}, {0, 1}, {1, 1}} // X = col, Y = row
+	for i, cell := range exp {
+		if got := ly.ChildAtGridPos(cell.Y, cell.X); got != ly.Child(i).(Node2D) {
+			t.Errorf("cell row: %v col: %v: %v, expected box%d", cell.Y, cell.X, got, i)
+		}
+		pos := ly.Child(i).(Node2D).AsWidget().LayState.Alloc.PosRel
+		if x := ly.GridData[Col][cell.X].AllocPosRel; pos.X != x {
+			t.Errorf("box%d x: %v, expected at col %v: %v", i, pos.X, cell.X, x)
+		}
+	}
+	for col := 0; col < 2; col++ {
+		if got := ly.ChildAtGridPos(0, col); got != wide.This().(Node2D) {
+			t.Errorf("cell 0, %d: %v, expected wide", col, got)
+		}
+	}
+
+	// explicit row and col with a col span: auto children wrap around it
+	vp = testViewport(400, 300)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("columns", 3)
+	span := addTestBox(ly, "span", 40, 20)
+	if err := ly.SetChildGridPos(span, 1, 1, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		addTestBox(ly, fmt.Sprintf("box%d", i), 20, 20)
+	}
+	vp.FullRender2DTree()
+	exp = []image.Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {0, 2}}
+	for i, cell := range exp {
+		if got := ly.ChildAtGridPos(cell.Y, cell.X); got == nil || got.Name() != fmt.Sprintf("box%d", i) {
+			t.Errorf("cell row: %v col: %v: %v, expected box%d", cell.Y, cell.X, got, i)
+		}
+	}
+	if ly.GridSize != image.Pt(3, 3) {
+		t.Errorf("grid size: %v, expected 3x3 with room for the span", ly.GridSize)
+	}
+}
+
+func TestGridTemplateAreas(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "dash", LayoutGrid)
+	ly.SetProp("grid-template-areas", "header header / nav main")
+	// added out of order: placement is by area name
+	main := addTestBox(ly, "main", 60, 50)
+	main.SetProp("grid-area", "main")
+	nav := addTestBox(ly, "nav", 40, 50)
+	nav.SetProp("grid-area", "nav")
+	hdr := addTestBox(ly, "header", 20, 20)
+	hdr.SetProp("grid-area", "header")
+	vp.FullRender2DTree()
+
+	if ly.GridSize != image.Pt(2, 2) {
+		t.Fatalf("grid size: %v, expected 2x2 from the template", ly.GridSize)
+	}
+	if ar := ly.GridAreas["header"]; ar != image.Rect(0, 0, 2, 1) {
+		t.Errorf("header area: %v, expected to span both columns of row 0", ar)
+	}
+	spc := ly.Spacing.Dots
+	hp, np, mp := hdr.LayState.Alloc.PosRel, nav.LayState.Alloc.PosRel, main.LayState.Alloc.PosRel
+	if hp.X != np.X || hp.Y >= np.Y {
+		t.Errorf("header at: %v, expected above nav at: %v", hp, np)
+	}
+	if np.Y != hp.Y+20+spc || mp.Y != np.Y {
+		t.Errorf("nav at: %v, main at: %v, expected both in row 1 below header at: %v", np, mp, hp)
+	}
+	if mp.X != np.X+40+spc {
+		t.Errorf("main at: %v, expected in col 1 right of nav at: %v", mp, np)
+	}
+
+	if _, err := ParseGridAreas([]string{"a b", "b a"}); err == nil {
+		t.Errorf("expected error for non-rectangular areas")
+	}
+	if _, err := ParseGridAreas([]string{"a b", "a"}); err == nil {
+		t.Errorf("expected error for ragged rows")
+	}
+}
+
+func TestGridTrackMinMax(t *testing.T) {
+	grid := func(width int) *Layout {
+		vp := testViewport(width, 300)
+		ly := AddNewLayout(vp, "grid", LayoutGrid)
+		ly.SetProp("columns", 2)
+		ly.SetProp("spacing", units.NewPx(10))
+		ly.SetProp("grid-template-columns", "minmax(100px, 1fr) 50px")
+		ly.SetStretchMax()
+		for i := 0; i < 4; i++ {
+			addTestBox(ly, "box", 20, 20)
+		}
+		vp.FullRender2DTree()
+		return ly
+	}
+	ly := grid(80)
+	if sz := ly.GridData[Col][0].AllocSize; sz != 100 {
+		t.Errorf("narrow col 0 size: %v, expected its 100px min", sz)
+	}
+	ly = grid(400)
+	avail := ly.LayState.Alloc.Size.X - 2*ly.BoxSpace()
+	c0, c1 := ly.GridData[Col][0].AllocSize, ly.GridData[Col][1].AllocSize
+	if c1 != 50 {
+		t.Errorf("wide col 1 size: %v, expected fixed 50px", c1)
+	}
+	if exp := avail - 50 - ly.Spacing.Dots; c0 <= 100 || mat32.Abs(c0-exp) > 0.01 {
+		t.Errorf("wide col 0 size: %v, expected to stretch to remaining: %v", c0, exp)
+	}
+
+	if _, err := ParseGridTracks("minmax(10px 1fr"); err == nil {
+		t.Errorf("expected error for unbalanced parens")
+	}
+	if _, err := ParseGridTracks("minmax(10px)"); err == nil {
+		t.Errorf("expected error for minmax with one arg")
+	}
+}
+
+func TestLayoutScrollReuse(t *testing.T) {
+	vp, ly := testScrollLayout(50, 50)
+	vp.FullRender2DTree()
+	if ly.Scrolls[mat32.X] != nil || ly.Scrolls[mat32.Y] != nil {
+		t.Fatalf("no scrollbar should be created without overflow")
+	}
+
+	box := ly.Child(0).(*Space)
+	box.SetFixedHeight(units.NewPx(300))
+	vp.FullRender2DTree()
+	sc := ly.Scrolls[mat32.Y]
+	if sc == nil || !ly.HasScroll[mat32.Y] {
+		t.Fatalf("expected V scrollbar on overflow")
+	}
+
+	box.SetFixedHeight(units.NewPx(50))
+	vp.FullRender2DTree()
+	if ly.HasScroll[mat32.Y] || ly.Scrolls[mat32.Y] != sc {
+		t.Errorf("scrollbar should be kept, inactive, when not overflowing")
+	}
+
+	box.SetFixedHeight(units.NewPx(300))
+	vp.FullRender2DTree()
+	if !ly.HasScroll[mat32.Y] || ly.Scrolls[mat32.Y] != sc {
+		t.Errorf("same scrollbar should be reused on overflow")
+	}
+	if ly.Scrolls[mat32.X] != nil {
+		t.Errorf("no H scrollbar should be created without H overflow")
+	}
+}
+
+func TestLayoutNegativeMargin(t *testing.T) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "avatars", LayoutHoriz)
+	var boxes []*Space
+	for i := 0; i < 3; i++ {
+		bx := addTestBox(ly, fmt.Sprintf("avatar%d", i), 40, 40)
+		bx.SetProp("margin", units.NewPx(-8))
+		boxes = append(boxes, bx)
+	}
+	vp.FullRender2DTree()
+
+	// margins are on all sides: each box advances by 40 - 2*8
+	for i, bx := range boxes {
+		if x := bx.LayState.Alloc.PosRel.X; x != float32(i*24) {
+			t.Errorf("avatar %d pos: %v, expected %v", i, x, i*24)
+		}
+	}
+	if need := ly.LayState.Size.Need.X; need != 72 {
+		t.Errorf("layout need: %v, expected net of negative margins: 72", need)
+	}
+	b0 := boxes[0].ChildrenBBox2D()
+	b1 := boxes[1].ChildrenBBox2D()
+	if ov := b0.Max.X - b1.Min.X; ov != 16 {
+		t.Errorf("avatar box overlap: %v, expected 16", ov)
+	}
+}
+
+func TestLayoutClear(t *testing.T) {
+	vp, ly := testScrollLayout(300, 300)
+	ly.Lay = LayoutStacked
+	addTestBox(ly, "box2", 20, 20)
+	ly.StackTop = 1
+	vp.FullRender2DTree()
+	if ly.Scrolls[mat32.X] == nil || ly.Scrolls[mat32.Y] == nil {
+		t.Fatalf("expected scrollbars before clear")
+	}
+
+	ly.Clear()
+	if ly.HasChildren() {
+		t.Errorf("children after clear: %v", ly.NumChildren())
+	}
+	if ly.StackTop != 0 {
+		t.Errorf("stack top after clear: %v, expected reset to 0", ly.StackTop)
+	}
+	if _, err := ly.ChildTry(ly.StackTop); err == nil {
+		t.Errorf("stack top should not refer to any child after clear")
+	}
+	if ly.Scrolls[mat32.X] != nil || ly.Scrolls[mat32.Y] != nil || ly.HasAnyScroll() {
+		t.Errorf("scrollbars after clear: %v, expected none", ly.HasScroll)
+	}
+	if ly.ChildSize != mat32.Vec2Zero || ly.ExtraSize != mat32.Vec2Zero {
+		t.Errorf("child size: %v extra: %v, expected zero after clear", ly.ChildSize, ly.ExtraSize)
+	}
+
+	addTestBox(ly, "fresh", 20, 20)
+	vp.FullRender2DTree()
+	if ly.NumChildren() != 1 || ly.HasAnyScroll() {
+		t.Errorf("layout should be usable after clear")
+	}
+}
+
+func TestLayoutFlexGrowShrink(t *testing.T) {
+	var avail float32
+	// an under-sized row: the 40px deficit comes from the shrinkable
+	// children in proportion to shrink * pref: 1 * 100 and 3 * 100
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "row", LayoutHoriz)
+	ly.SetProp("spacing", units.NewPx(0))
+	ly.SetFixedWidth(units.NewPx(160))
+	for i, shr := range []float32{1, 3} {
+		sp := AddNewSpace(ly, fmt.Sprintf("box%d", i))
+		sp.SetProp("width", units.NewPx(100))
+		sp.SetProp("min-width", units.NewPx(20))
+		sp.SetProp("flex-shrink", shr)
+	}
+	vp.FullRender2DTree()
+	for i, exp := range []float32{90, 70} {
+		if w := ly.Child(i).(Node2D).AsWidget().LayState.Alloc.Size.X; mat32.Abs(w-exp) > 0.01 {
+			t.Errorf("shrink child %d width: %v, expected: %v", i, w, exp)
+		}
+	}
+
+	// shrinking is not enough: the shrinkable child stays at its min width,
+	// and the other goes from its own min width, so they still fit
+	vp = testViewport(400, 300)
+	outer = AddNewLayout(vp, "outer", LayoutVert)
+	ly = AddNewLayout(outer, "row", LayoutHoriz)
+	ly.SetProp("spacing", units.NewPx(0))
+	ly.SetFixedWidth(units.NewPx(120))
+	for i, mn := range []float32{90, 10} {
+		sp := AddNewSpace(ly, fmt.Sprintf("box%d", i))
+		sp.SetProp("width", units.NewPx(100))
+		sp.SetProp("min-width", units.NewPx(mn))
+	}
+	ly.Child(0).SetProp("flex-shrink", 1)
+	vp.FullRender2DTree()
+	avail = ly.LayState.Alloc.Size.X - 2*ly.BoxSpace()
+	for i, exp := range []float32{90, avail - 90} {
+		if w := ly.Child(i).(Node2D).AsWidget().LayState.Alloc.Size.X; mat32.Abs(w-exp) > 0.01 {
+			t.Errorf("over-shrink child %d width: %v, expected: %v", i, w, exp)
+		}
+	}
+	if ly.HasScroll[mat32.X] {
+		t.Errorf("over-shrink row has a horizontal scrollbar")
+	}
+
+	// extra space: both grow equally, but the first is capped at its max
+	// width, with the rest going to the second
+	vp = testViewport(300, 100)
+	ly = AddNewLayout(vp, "row", LayoutHoriz)
+	ly.SetProp("spacing", units.NewPx(0))
+	for i := 0; i < 2; i++ {
+		sp := AddNewSpace(ly, fmt.Sprintf("box%d", i))
+		sp.SetProp("width", units.NewPx(100))
+		sp.SetProp("flex-grow", 1)
+	}
+	ly.Child(0).SetProp("max-width", units.NewPx(120))
+	vp.FullRender2DTree()
+	avail = ly.LayState.Alloc.Size.X - 2*ly.BoxSpace()
+	for i, exp := range []float32{120, avail - 120} {
+		if w := ly.Child(i).(Node2D).AsWidget().LayState.Alloc.Size.X; mat32.Abs(w-exp) > 0.01 {
+			t.Errorf("grow child %d width: %v, expected: %v", i, w, exp)
+		}
+	}
+}
+
+func TestGridRowAligns(t *testing.T) {
+	vp := testViewport(400, 300)
+	outer := AddNewLayout(vp, "outer", LayoutVert)
+	ly := AddNewLayout(outer, "grid", LayoutGrid)
+	ly.SetProp("columns", 2)
+	// a 20x20 box next to a 20x60 box in each of two rows
+	head := addTestBox(ly, "head", 20, 20)
+	headTall := addTestBox(ly, "head-tall", 20, 60)
+	body := addTestBox(ly, "body", 20, 20)
+	bodyTall := addTestBox(ly, "body-tall", 20, 60)
+	offs := func() (hy, by float32) {
+		vp.FullRender2DTree()
+		return head.LayState.Alloc.PosRel.Y - headTall.LayState.Alloc.PosRel.Y, body.LayState.Alloc.PosRel.Y - bodyTall.LayState.Alloc.PosRel.Y
+	}
+	_, def := offs()
+
+	ly.SetGridRowAlign(0, GridItemsEnd)
+	if hy, by := offs(); hy != 40 || by != def {
+		t.Errorf("row offsets in cells: %v, %v, expected bottom 40 in the header row and default %v in the body row", hy, by, def)
+	}
+	// the row alignment overrides the items alignment of the grid
+	ly.SetProp("grid-align-items", GridItemsStart)
+	if hy, by := offs(); hy != 40 || by != 0 {
+		t.Errorf("row over items offsets in cells: %v, %v, expected 40, 0", hy, by)
+	}
+	// the explicit alignment of the child overrides the row
+	head.SetProp("vertical-align", gist.AlignTop)
+	if hy, _ := offs(); hy != 0 {
+		t.Errorf("explicit header offset in cell: %v, expected 0", hy)
+	}
+	head.DeleteProp("vertical-align")
+	ly.SetGridRowAlign(0, GridItemsUnset)
+	if hy, _ := offs(); hy != 0 {
+		t.Errorf("unset row offset in cell: %v, expected items start 0", hy)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LayoutProfile turns on accumulation of layout pass counts and GatherSizes
+// timing per Layout node into LayoutProf -- stats are reset at the start of
+// each full render of a top-level Viewport2D (i.e., per frame).  Can be set
+// in PrefsDebug from prefs gui.
+var LayoutProfile = false
+
+// LayoutProf holds the layout profiling stats accumulated when LayoutProfile
+// is on.
+var LayoutProf LayoutProfileStats
+
+// LayoutProfileNode records layout profiling stats for one Layout node
+type LayoutProfileNode struct {
+	Path     string        `desc:"path of the layout node"`
+	Size2D   int           `desc:"number of Size2D passes"`
+	Layout2D int           `desc:"number of Layout2D passes"`
+	Gather   time.Duration `desc:"total time spent in GatherSizes within Size2D"`
+}
+
+// LayoutProfileStats accumulates layout pass counts and timing across Layout
+// nodes, when LayoutProfile is on.
+type LayoutProfileStats struct {
+	Size2D   int                            `desc:"total number of Layout Size2D passes"`
+	Layout2D int                            `desc:"total number of Layout Layout2D passes"`
+	Gather   time.Duration                  `desc:"total time spent in GatherSizes"`
+	Nodes    map[*Layout]*LayoutProfileNode `desc:"per-node stats"`
+	Mu       sync.Mutex                     `view:"-" desc:"mutex protecting the stats"`
+}
+
+// Reset resets all the stats
+func (lp *LayoutProfileStats) Reset() {
+	lp.Mu.Lock()
+	lp.Size2D = 0
+	lp.Layout2D = 0
+	lp.Gather = 0
+	lp.Nodes = nil
+	lp.Mu.Unlock()
+}
+
+// node returns the stats for given layout, creating if needed -- must be
+// called under mutex
+func (lp *LayoutProfileStats) node(ly *Layout) *LayoutProfileNode {
+	if lp.Nodes == nil {
+		lp.Nodes = make(map[*Layout]*LayoutProfileNode)
+	}
+	nd, ok := lp.Nodes[ly]
+	if !ok {
+		nd = &LayoutProfileNode{Path: ly.Path()}
+		lp.Nodes[ly] = nd
+	}
+	return nd
+}
+
+// AddSize2D records a Size2D pass for given layout, with the time taken
+// to gather sizes.
+func (lp *LayoutProfileStats) AddSize2D(ly *Layout, gather time.Duration) {
+	lp.Mu.Lock()
+	nd := lp.node(ly)
+	nd.Size2D++
+	nd.Gather += gather
+	lp.Size2D++
+	lp.Gather += gather
+	lp.Mu.Unlock()
+}
+
+// AddLayout2D records a Layout2D pass for given layout.
+func (lp *LayoutProfileStats) AddLayout2D(ly *Layout) {
+	lp.Mu.Lock()
+	lp.node(ly).Layout2D++
+	lp.Layout2D++
+	lp.Mu.Unlock()
+}
+
+// Node returns a copy of the stats for given layout, and false if there
+// are none.
+func (lp *LayoutProfileStats) Node(ly *Layout) (LayoutProfileNode, bool) {
+	lp.Mu.Lock()
+	defer lp.Mu.Unlock()
+	nd, ok := lp.Nodes[ly]
+	if !ok {
+		return LayoutProfileNode{}, false
+	}
+	return *nd, true
+}
+
+// Slowest returns copies of the stats for up to n nodes, sorted by time
+// spent in GatherSizes, slowest first -- if n <= 0 all nodes are returned.
+func (lp *LayoutProfileStats) Slowest(n int) []LayoutProfileNode {
+	lp.Mu.Lock()
+	nds := make([]LayoutProfileNode, 0, len(lp.Nodes))
+	for _, nd := range lp.Nodes {
+		nds = append(nds, *nd)
+	}
+	lp.Mu.Unlock()
+	sort.Slice(nds, func(i, j int) bool {
+		return nds[i].Gather > nds[j].Gather
+	})
+	if n > 0 && n < len(nds) {
+		nds = nds[:n]
+	}
+	return nds
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
 	"github.com/goki/prof"
 )
 
@@ -66,7 +67,8 @@ For Widget / Layout nodes, rendering is done in 5 separate passes:
 */
 type Node2DBase struct {
 	NodeBase
-	Viewport *Viewport2D `copy:"-" json:"-" xml:"-" view:"-" desc:"our viewport -- set in Init2D (Base typically) and used thereafter -- use ViewportSafe() method to access under BBoxMu read lock"`
+	Viewport    *Viewport2D `copy:"-" json:"-" xml:"-" view:"-" desc:"our viewport -- set in Init2D (Base typically) and used thereafter -- use ViewportSafe() method to access under BBoxMu read lock"`
+	TraceLayout bool        `copy:"-" json:"-" xml:"-" desc:"reports a trace of the layout passes for just this node -- OR'd with the global Layout2DTrace"`
 }
 
 var KiT_Node2DBase = kit.Types.AddType(&Node2DBase{}, Node2DBaseProps)
@@ -95,8 +97,13 @@ var Render2DTrace bool = false
 
 // Layout2DTrace reports a trace of all layouts (just
 // printfs to stdout) -- can be set in PrefsDebug from prefs gui
+// -- see also TraceLayout on individual nodes
 var Layout2DTrace bool = false
 
+// Layout2DTracef is the function used to output layout trace messages --
+// can be replaced to capture the trace output elsewhere
+var Layout2DTracef = fmt.Printf
+
 // Node2D is the interface for all 2D nodes -- defines the stages of building
 // and rendering the 2D scenegraph
 type Node2D interface {
@@ -171,6 +178,13 @@ type Node2D interface {
 	// down as parBBox do the children's Layout2D.
 	ChildrenBBox2D() image.Rectangle
 
+	// MinContentSize returns the minimum size that would fit all of the
+	// content of this node without clipping, regardless of its current
+	// allocation -- gathered bottom-up from the Need sizes computed in the
+	// last Size2D pass, without modifying any layout state.  Layouts combine
+	// the sizes of their children according to the type of layout.
+	MinContentSize() mat32.Vec2
+
 	// Render2D: Final rendering pass, each node is fully responsible for
 	// calling Render2D on its own children, to provide maximum flexibility
 	// (see Render2DChildren for default impl) -- bracket the render calls in
@@ -304,6 +318,12 @@ func (nb *Node2DBase) AsWidget() *WidgetBase {
 	return nil
 }
 
+// LayoutTraceOn returns true if layout tracing is on for this node, either
+// globally via Layout2DTrace or just for this node via TraceLayout
+func (nb *Node2DBase) LayoutTraceOn() bool {
+	return Layout2DTrace || nb.TraceLayout
+}
+
 func (nb *Node2DBase) Init2D() {
 }
 
@@ -328,6 +348,16 @@ func (nb *Node2DBase) ChildrenBBox2D() image.Rectangle {
 	return image.ZR
 }
 
+func (nb *Node2DBase) MinContentSize() mat32.Vec2 {
+	if nb.This() == nil {
+		return mat32.Vec2Zero
+	}
+	if wb := nb.This().(Node2D).AsWidget(); wb != nil {
+		return wb.LayState.Size.Need
+	}
+	return mat32.Vec2Zero
+}
+
 func (nb *Node2DBase) Render2D() {
 }
 
@@ -834,15 +864,17 @@ func (nb *Node2DBase) Layout2DTree() {
 	nbi := nb.This().(Node2D)
 	redo := nbi.Layout2D(parBBox, 0) // important to use interface version to get interface!
 	if redo {
-		if Layout2DTrace {
-			fmt.Printf("Layout: ----------  Redo: %v ----------- \n", nbi.Path())
+		if nb.LayoutTraceOn() {
+			Layout2DTracef("Layout: ----------  Redo: %v ----------- \n", nbi.Path())
 		}
 		wb := nbi.AsWidget()
 		if wb != nil {
 			la := wb.LayState.Alloc
+			nb.ResetAllocs2DTree() // so Reset keeps Prev from the last pass
 			wb.Size2DTree(1)
 			wb.LayState.Alloc = la
 		} else {
+			nb.ResetAllocs2DTree()
 			nb.Size2DTree(1)
 		}
 		nbi.Layout2D(parBBox, 1) // todo: multiple iters?
@@ -850,6 +882,23 @@ func (nb *Node2DBase) Layout2DTree() {
 	pr.End()
 }
 
+// ResetAllocs2DTree resets the layout allocations of all the widgets in
+// the tree, before the sizing for a redo iteration of the same layout pass:
+// LayoutState.Reset only saves an allocation as the Prev one of the last
+// pass if it has not been reset yet in this pass.
+func (nb *Node2DBase) ResetAllocs2DTree() {
+	nb.FuncDownMeFirst(0, nb.This(), func(k ki.Ki, level int, d interface{}) bool {
+		nii, ni := KiToNode2D(k)
+		if nii == nil || ni.IsDeleted() || ni.IsDestroyed() {
+			return ki.Break
+		}
+		if wb := nii.AsWidget(); wb != nil {
+			wb.LayState.Alloc.Reset()
+		}
+		return ki.Continue
+	})
+}
+
 // Render2DTree just calls on parent node and it takes full responsibility for
 // managing the children -- this allows maximum flexibility for order etc of
 // rendering
@@ -1049,6 +1098,9 @@ func (nb *Node2DBase) SetFixedHeight(val units.Value) {
 	nb.SetProp("max-height", val)
 }
 
+// todo: SetMargin / SetPadding(top, right, bottom, left) setters, once the
+// style supports per-side margin and padding (see gist.Margins)
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // MetaData2D
 
@@ -1009,6 +1009,8 @@ type PrefsDebug struct {
 
 	Layout2DTrace *bool `desc:"reports trace of all layouts (printfs to stdout)"`
 
+	LayoutProfile *bool `desc:"accumulates layout pass counts and timing per layout node into gi.LayoutProf, reset per frame"`
+
 	WinEventTrace *bool `desc:"reports trace of window events (printfs to stdout)"`
 
 	WinPublishTrace *bool `desc:"reports the stack trace leading up to win publish events which are expensive -- wrap multiple updates in UpdateStart / End to prevent"`
@@ -1052,6 +1054,7 @@ func (pf *PrefsDebug) Connect() {
 	pf.Update2DTrace = &Update2DTrace
 	pf.Render2DTrace = &Render2DTrace
 	pf.Layout2DTrace = &Layout2DTrace
+	pf.LayoutProfile = &LayoutProfile
 	pf.WinEventTrace = &WinEventTrace
 	pf.WinPublishTrace = &WinPublishTrace
 	pf.WinDrawTrace = &WinDrawTrace
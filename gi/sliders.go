@@ -48,6 +48,7 @@ type SliderBase struct {
 	ThSize      float32                   `xml:"-" desc:"computed size of the thumb -- if ValThumb then this is auto-sized based on ThumbVal and is subtracted from Size in computing Value -- this is the display size version subject to SliderMinThumbSize"`
 	ThSizeReal  float32                   `xml:"-" desc:"computed size of the thumb, without any SliderMinThumbSize limitation -- use this for more accurate calculations of true value"`
 	ThumbSize   units.Value               `xml:"thumb-size" desc:"styled fixed size of the thumb"`
+	MinThSize   float32                   `xml:"-" desc:"minimum size of an auto-sized ValThumb thumb, in dots -- if 0, SliderMinThumbSize is used"`
 	Prec        int                       `xml:"prec" desc:"specifies the precision of decimal places (total, not after the decimal point) to use in representing the number -- this helps to truncate small weird floating point values in the nether regions"`
 	Icon        IconName                  `view:"show-name" desc:"optional icon for the dragging knob"`
 	ValThumb    bool                      `xml:"val-thumb" alt:"prop-thumb" desc:"if true, has a proportionally-sized thumb knob reflecting another value -- e.g., the amount visible in a scrollbar, and thumb is completely inside Size -- otherwise ThumbSize affects Size so that full Size range can be traversed"`
@@ -83,6 +84,7 @@ func (sb *SliderBase) CopyFieldsFrom(frm interface{}) {
 	sb.ThSize = fr.ThSize
 	sb.ThSizeReal = fr.ThSizeReal
 	sb.ThumbSize = fr.ThumbSize
+	sb.MinThSize = fr.MinThSize
 	sb.Prec = fr.Prec
 	sb.Icon = fr.Icon
 	sb.ValThumb = fr.ValThumb
@@ -179,6 +181,7 @@ func (sb *SliderBase) SetSliderState(state SliderStates) {
 	}
 	sb.State = state
 	sb.Sty = sb.StateStyles[state] // get relevant styles
+	sb.LayState.BoxSpcOk = false   // style replaced
 }
 
 // SliderPress sets the slider in the down state -- mouse clicked down but
@@ -356,7 +359,11 @@ func (sb *SliderBase) UpdateThumbValSize() {
 	sb.ThSizeReal = mat32.Min(sb.ThSizeReal, 1.0)
 	sb.ThSizeReal = mat32.Max(sb.ThSizeReal, 0.0)
 	sb.ThSizeReal *= sb.Size
-	sb.ThSize = mat32.Max(sb.ThSizeReal, SliderMinThumbSize)
+	minth := SliderMinThumbSize
+	if sb.MinThSize > 0 {
+		minth = mat32.Min(sb.MinThSize, sb.Size)
+	}
+	sb.ThSize = mat32.Max(sb.ThSizeReal, minth)
 }
 
 func (sb *SliderBase) KeyInput(kt *key.ChordEvent) {
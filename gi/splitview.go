@@ -35,10 +35,17 @@ import (
 // displayed within each region.
 type SplitView struct {
 	PartsWidgetBase
-	HandleSize  units.Value `xml:"handle-size" desc:"size of the handle region in the middle of each split region, where the splitter can be dragged -- other-dimension size is 2x of this"`
-	Splits      []float32   `desc:"proportion (0-1 normalized, enforced) of space allocated to each element -- can enter 0 to collapse a given element"`
-	SavedSplits []float32   `desc:"A saved version of the splits which can be restored -- for dynamic collapse / expand operations"`
-	Dim         mat32.Dims  `desc:"dimension along which to split the space"`
+	HandleSize  units.Value      `xml:"handle-size" desc:"size of the handle region in the middle of each split region, where the splitter can be dragged -- other-dimension size is 2x of this"`
+	Splits      []float32        `desc:"proportion (0-1 normalized, enforced) of space allocated to each element -- can enter 0 to collapse a given element"`
+	SavedSplits []float32        `desc:"A saved version of the splits which can be restored -- for dynamic collapse / expand operations"`
+	ResizeMode  SplitResizeModes `xml:"resize-mode" desc:"how the splits are adjusted when the size of the SplitView changes"`
+	DoubleClick SplitDblClicks   `xml:"double-click" desc:"what happens when a splitter is double-clicked"`
+	PrevAvail   float32          `copy:"-" json:"-" xml:"-" view:"-" desc:"space available to the elements on the previous layout -- used for ResizeMode"`
+	PrevKeep    float32          `copy:"-" json:"-" xml:"-" view:"-" desc:"size of the element kept by ResizeMode on the previous layout"`
+	NeedSizes   []float32        `copy:"-" json:"-" xml:"-" view:"-" desc:"size reserved for the Need of each flexible element on the previous layout, before the extra space is distributed according to the splits -- used to map splitter positions back to splits"`
+	ExtraSize   float32          `copy:"-" json:"-" xml:"-" view:"-" desc:"space distributed according to the splits on the previous layout, beyond the NeedSizes and fixed sizes"`
+	FixedSizes  []units.Value    `desc:"optional fixed sizes for each element, along the split dimension -- a 0 (or NaN) value means the element is flexible and gets its proportion of the space remaining after all fixed elements"`
+	Dim         mat32.Dims       `desc:"dimension along which to split the space"`
 }
 
 var KiT_SplitView = kit.Types.AddType(&SplitView{}, SplitViewProps)
@@ -54,9 +61,66 @@ func (sv *SplitView) CopyFieldsFrom(frm interface{}) {
 	sv.HandleSize = fr.HandleSize
 	mat32.CopyFloat32s(&sv.Splits, fr.Splits)
 	mat32.CopyFloat32s(&sv.SavedSplits, fr.SavedSplits)
+	sv.ResizeMode = fr.ResizeMode
+	sv.DoubleClick = fr.DoubleClick
+	sv.FixedSizes = make([]units.Value, len(fr.FixedSizes))
+	copy(sv.FixedSizes, fr.FixedSizes)
 	sv.Dim = fr.Dim
 }
 
+// SplitResizeModes determine how the splits of a SplitView are adjusted
+// when its size changes
+type SplitResizeModes int32
+
+const (
+	// SplitResizeProportional keeps the proportions of all the elements
+	SplitResizeProportional SplitResizeModes = iota
+
+	// SplitResizeKeepFirst keeps the first element at its prior size, while
+	// the others absorb the change in size
+	SplitResizeKeepFirst
+
+	// SplitResizeKeepLast keeps the last element at its prior size, while
+	// the others absorb the change in size
+	SplitResizeKeepLast
+
+	SplitResizeModesN
+)
+
+//go:generate stringer -type=SplitResizeModes
+
+var KiT_SplitResizeModes = kit.Enums.AddEnumAltLower(SplitResizeModesN, kit.NotBitFlag, nil, "SplitResize")
+
+func (ev SplitResizeModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *SplitResizeModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// SplitDblClicks are the actions taken when a splitter of a SplitView is
+// double-clicked
+type SplitDblClicks int32
+
+const (
+	// SplitDblClickCollapse collapses the element before the splitter,
+	// saving the prior splits -- or restores the saved splits if it is
+	// already collapsed
+	SplitDblClickCollapse SplitDblClicks = iota
+
+	// SplitDblClickEqualize gives the two elements on either side of the
+	// splitter equal shares of their combined space
+	SplitDblClickEqualize
+
+	// SplitDblClickRestore restores the saved splits, if any
+	SplitDblClickRestore
+
+	SplitDblClicksN
+)
+
+//go:generate stringer -type=SplitDblClicks
+
+var KiT_SplitDblClicks = kit.Enums.AddEnumAltLower(SplitDblClicksN, kit.NotBitFlag, nil, "SplitDblClick")
+
+func (ev SplitDblClicks) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *SplitDblClicks) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
 var SplitViewProps = ki.Props{
 	"EnumType:Flag": KiT_NodeFlags,
 	"handle-size":   units.NewPx(10),
@@ -67,7 +131,8 @@ var SplitViewProps = ki.Props{
 }
 
 // UpdateSplits updates the splits to be same length as number of children,
-// and normalized
+// and normalized -- any NaN, Inf or negative splits are treated as 0, and
+// if that leaves nothing, the splits are reset to even splits.
 func (sv *SplitView) UpdateSplits() {
 	sz := len(sv.Kids)
 	if sz == 0 {
@@ -77,7 +142,11 @@ func (sv *SplitView) UpdateSplits() {
 		sv.Splits = make([]float32, sz)
 	}
 	sum := float32(0.0)
-	for _, sp := range sv.Splits {
+	for i, sp := range sv.Splits {
+		if mat32.IsNaN(sp) || mat32.IsInf(sp, 0) || sp < 0 { // e.g., from a divide by zero
+			sv.Splits[i] = 0
+			continue
+		}
 		sum += sp
 	}
 	if sum == 0 { // set default even splits
@@ -107,13 +176,28 @@ func (sv *SplitView) EvenSplits() {
 // child entirely -- just does the basic local update start / end
 // use SetSplitsAction to trigger full rebuild which is typically required
 func (sv *SplitView) SetSplits(splits ...float32) {
-	updt := sv.UpdateStart()
-	sv.UpdateSplits()
+	sv.SetSplitsNoUpdate(splits...)
+	sv.UpdateNow()
+}
+
+// SetSplitsNoUpdate sets the split proportions without normalizing them or
+// triggering any update -- use this to set splits multiple times, e.g., when
+// restoring a saved configuration, followed by a single UpdateNow call.
+func (sv *SplitView) SetSplitsNoUpdate(splits ...float32) {
 	sz := len(sv.Kids)
+	if len(sv.Splits) != sz {
+		sv.UpdateSplits()
+	}
 	mx := ints.MinInt(sz, len(splits))
 	for i := 0; i < mx; i++ {
 		sv.Splits[i] = splits[i]
 	}
+}
+
+// UpdateNow normalizes the current splits and does the basic local update
+// start / end -- commits any changes made with SetSplitsNoUpdate.
+func (sv *SplitView) UpdateNow() {
+	updt := sv.UpdateStart()
 	sv.UpdateSplits()
 	sv.UpdateEnd(updt)
 }
@@ -134,6 +218,50 @@ func (sv *SplitView) SetSplitsAction(splits ...float32) {
 	sv.ViewportSafe().SetNeedsFullRender()
 }
 
+// SetHandleSize sets the size of the splitter handle regions -- this is set
+// as the handle-size property so that it persists across re-styling, and
+// triggers a full re-render to re-layout the children with the new size.
+func (sv *SplitView) SetHandleSize(sz units.Value) {
+	updt := sv.UpdateStart()
+	sv.SetProp("handle-size", sz)
+	sv.HandleSize = sz
+	sv.SetFullReRender()
+	sv.UpdateEnd(updt)
+}
+
+// SetFixedSizes sets the fixed sizes for each element -- a 0 value means the
+// element is flexible, sharing the remaining space according to its split.
+func (sv *SplitView) SetFixedSizes(szs ...units.Value) {
+	updt := sv.UpdateStart()
+	sv.FixedSizes = szs
+	sv.SetFullReRender()
+	sv.UpdateEnd(updt)
+}
+
+// SetDim sets the dimension along which to split the space, and triggers a
+// full re-render to re-layout the children and reposition the splitter
+// handles in the new orientation -- the splits are preserved.
+func (sv *SplitView) SetDim(dim mat32.Dims) {
+	if sv.Dim == dim {
+		return
+	}
+	updt := sv.UpdateStart()
+	sv.Dim = dim
+	sv.PrevAvail = 0 // sizes along the other dim do not apply for ResizeMode
+	sv.ConfigSplitters()
+	sv.SetFullReRender()
+	sv.UpdateEnd(updt)
+}
+
+// IsFixed returns true if given element has a fixed size
+func (sv *SplitView) IsFixed(idx int) bool {
+	if idx < 0 || idx >= len(sv.FixedSizes) {
+		return false
+	}
+	fv := sv.FixedSizes[idx].Val
+	return fv > 0 && !mat32.IsNaN(fv)
+}
+
 // SaveSplits saves the current set of splits in SavedSplits, for a later RestoreSplits
 func (sv *SplitView) SaveSplits() {
 	sz := len(sv.Splits)
@@ -196,12 +324,173 @@ func (sv *SplitView) IsCollapsed(idx int) bool {
 	return false
 }
 
+// IsMaximized returns true if given child is maximized, taking up all of
+// the space, with all others collapsed
+func (sv *SplitView) IsMaximized(idx int) bool {
+	sz := len(sv.Kids)
+	if idx < 0 || idx >= sz || len(sv.Splits) != sz {
+		return false
+	}
+	return sv.Splits[idx] > 0.99
+}
+
+// MaximizeChild maximizes given child, collapsing all the others, saving
+// the prior splits -- if the child is already maximized, the saved splits
+// are restored instead, so a second call toggles back -- does an Update
+func (sv *SplitView) MaximizeChild(idx int) {
+	sz := len(sv.Kids)
+	if idx < 0 || idx >= sz {
+		return
+	}
+	if sv.IsMaximized(idx) && sv.SavedSplits != nil {
+		sv.RestoreSplits()
+		return
+	}
+	sv.SaveSplits()
+	splits := make([]float32, sz)
+	splits[idx] = 1
+	sv.SetSplitsAction(splits...)
+}
+
+// IsEven returns true if the splits are all equal
+func (sv *SplitView) IsEven() bool {
+	sz := len(sv.Kids)
+	if sz == 0 || len(sv.Splits) != sz {
+		return false
+	}
+	even := 1.0 / float32(sz)
+	for _, sp := range sv.Splits {
+		if mat32.Abs(sp-even) > 0.001 {
+			return false
+		}
+	}
+	return true
+}
+
+// ResetSplits resets to even splits across all children, saving the prior
+// splits -- if the splits are already even, the saved splits are restored
+// instead, so a second call toggles back -- does an Update
+func (sv *SplitView) ResetSplits() {
+	if len(sv.Kids) == 0 {
+		return
+	}
+	if sv.IsEven() && sv.SavedSplits != nil {
+		sv.RestoreSplits()
+		return
+	}
+	sv.SaveSplits()
+	updt := sv.UpdateStart()
+	sv.UpdateSplits()
+	sv.EvenSplits()
+	sv.ViewportSafe().SetNeedsFullRender() // splits typically require full rebuild
+	sv.UpdateEnd(updt)
+}
+
+// SplitterDoubleClick performs the DoubleClick action for given splitter,
+// which is between elements idx and idx+1
+func (sv *SplitView) SplitterDoubleClick(idx int) {
+	sz := len(sv.Kids)
+	if idx < 0 || idx >= sz-1 {
+		return
+	}
+	switch sv.DoubleClick {
+	case SplitDblClickCollapse:
+		if sv.IsCollapsed(idx) {
+			sv.RestoreSplits()
+		} else {
+			sv.CollapseChild(true, idx)
+		}
+	case SplitDblClickEqualize:
+		if len(sv.Splits) != sz {
+			sv.UpdateSplits()
+		}
+		splits := make([]float32, sz)
+		copy(splits, sv.Splits)
+		even := 0.5 * (splits[idx] + splits[idx+1])
+		splits[idx] = even
+		splits[idx+1] = even
+		sv.SetSplitsAction(splits...)
+	case SplitDblClickRestore:
+		sv.RestoreSplits()
+	}
+}
+
+// CollapsedPane returns the index of a collapsed pane adjacent to given
+// splitter (between elements idx and idx+1), preferring the one before it,
+// or -1 if neither is collapsed
+func (sv *SplitView) CollapsedPane(idx int) int {
+	sz := len(sv.Kids)
+	if idx < 0 || idx >= sz-1 || len(sv.Splits) != sz {
+		return -1
+	}
+	switch {
+	case sv.IsCollapsed(idx):
+		return idx
+	case sv.IsCollapsed(idx + 1):
+		return idx + 1
+	}
+	return -1
+}
+
+// ExpandPane expands given collapsed pane, restoring the saved splits if
+// they give it some space, or otherwise giving it an even share -- does an
+// Update
+func (sv *SplitView) ExpandPane(idx int) {
+	sz := len(sv.Kids)
+	if idx < 0 || idx >= sz {
+		return
+	}
+	if len(sv.SavedSplits) == sz && sv.SavedSplits[idx] > 0.01 {
+		sv.RestoreSplits()
+		return
+	}
+	sv.RestoreChild(idx)
+}
+
+// SplitterClick performs the action for a click on given splitter: if a
+// pane adjacent to it is collapsed, that pane is expanded -- returns true
+// if so, and false if the click should start a drag as usual
+func (sv *SplitView) SplitterClick(idx int) bool {
+	cp := sv.CollapsedPane(idx)
+	if cp < 0 {
+		return false
+	}
+	sv.ExpandPane(cp)
+	return true
+}
+
+// SplitterIcon returns the icon for given splitter: an expand arrow pointing
+// away from an adjacent collapsed pane, or else the usual handle
+func (sv *SplitView) SplitterIcon(idx int) IconName {
+	cp := sv.CollapsedPane(idx)
+	switch {
+	case cp < 0 && sv.Dim == mat32.X:
+		return IconName("handle-circles-vert")
+	case cp < 0:
+		return IconName("handle-circles-horiz")
+	case sv.Dim == mat32.X && cp == idx:
+		return IconName("wedge-right")
+	case sv.Dim == mat32.X:
+		return IconName("wedge-left")
+	case cp == idx:
+		return IconName("wedge-down")
+	}
+	return IconName("wedge-up")
+}
+
 // SetSplitAction sets the new splitter value, for given splitter -- new
-// value is 0..1 value of position of that splitter -- it is a sum of all the
-// positions up to that point.  Splitters are updated to ensure that selected
-// position is achieved, while dividing remainder appropriately.
+// value is 0..1 value of position of that splitter, as a proportion of the
+// available space, as set by Layout2D -- it is mapped to the sum of all the
+// splits up to that point by SplitSumFromPos.  Splitters are updated to
+// ensure that selected position is achieved, while dividing remainder
+// appropriately.
 func (sv *SplitView) SetSplitAction(idx int, nwval float32) {
 	sz := len(sv.Splits)
+	nwval, ok := sv.SplitSumFromPos(idx, nwval)
+	if !ok || sv.IsSplitterFixed(idx) { // splits have no effect
+		sv.ViewportSafe().SetNeedsFullRender() // puts splitter back
+		return
+	}
 	oldsum := float32(0)
 	for i := 0; i <= idx; i++ {
 		oldsum += sv.Splits[i]
@@ -214,20 +503,29 @@ func (sv *SplitView) SetSplitAction(idx int, nwval float32) {
 		delta = -oldval
 		nwval = oldsum + delta
 	}
-	rmdr := 1 - nwval
-	if idx < sz-1 {
-		oldrmdr := 1 - oldsum
-		if oldrmdr <= 0 {
-			if rmdr > 0 {
-				dper := rmdr / float32((sz-1)-idx)
-				for i := idx + 1; i < sz; i++ {
-					sv.Splits[i] = dper
-				}
-			}
+	// the remainder goes to the flexible elements after idx -- the splits of
+	// fixed elements are left as is, as they do not affect their size
+	fixRmdr := float32(0)
+	nflex := 0
+	for i := idx + 1; i < sz; i++ {
+		if sv.IsFixed(i) {
+			fixRmdr += sv.Splits[i]
 		} else {
-			for i := idx + 1; i < sz; i++ {
-				curval := sv.Splits[i]
-				sv.Splits[i] = rmdr * (curval / oldrmdr) // proportional
+			nflex++
+		}
+	}
+	rmdr := 1 - nwval - fixRmdr
+	if nflex > 0 {
+		oldrmdr := 1 - oldsum - fixRmdr
+		for i := idx + 1; i < sz; i++ {
+			if sv.IsFixed(i) {
+				continue
+			}
+			switch {
+			case oldrmdr <= 0:
+				sv.Splits[i] = mat32.Max(rmdr, 0) / float32(nflex)
+			default:
+				sv.Splits[i] = rmdr * (sv.Splits[i] / oldrmdr) // proportional
 			}
 		}
 	}
@@ -238,6 +536,59 @@ func (sv *SplitView) SetSplitAction(idx int, nwval float32) {
 	sv.ViewportSafe().SetNeedsFullRender() // splits typically require full rebuild
 }
 
+// IsSplitterFixed returns true if the splitter after given element cannot
+// move, because that element has a fixed size, or all the elements after
+// it do -- such splitters are inactive.
+func (sv *SplitView) IsSplitterFixed(idx int) bool {
+	if sv.IsFixed(idx) {
+		return true
+	}
+	for i := idx + 1; i < len(sv.Kids); i++ {
+		if !sv.IsFixed(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitSumFromPos returns the sum of the splits up to and including given
+// index that puts the splitter after it at given position, as a 0..1
+// proportion of the space available on the previous layout -- the inverse
+// of the splitter positions set by Layout2D, where each flexible element
+// gets its NeedSizes plus its split proportion of the ExtraSize.  Returns
+// false if there is no extra space, so the splits have no effect.
+func (sv *SplitView) SplitSumFromPos(idx int, pos float32) (float32, bool) {
+	sz := len(sv.Splits)
+	if sv.PrevAvail <= 0 || sv.ExtraSize <= 0 || len(sv.NeedSizes) != sz {
+		return 0, false
+	}
+	flexSum := float32(0)
+	for i, sp := range sv.Splits {
+		if !sv.IsFixed(i) {
+			flexSum += sp
+		}
+	}
+	base := float32(0) // sizes not from the splits, through idx
+	fixSum := float32(0)
+	prvSum := float32(0) // flexible splits before idx
+	for i := 0; i <= idx; i++ {
+		if sv.IsFixed(i) {
+			if !sv.IsCollapsed(i) {
+				base += sv.FixedSizes[i].Dots
+			}
+			fixSum += sv.Splits[i]
+			continue
+		}
+		base += sv.NeedSizes[i]
+		if i < idx {
+			prvSum += sv.Splits[i]
+		}
+	}
+	flex := (pos*sv.PrevAvail - base) * flexSum / sv.ExtraSize
+	flex = mat32.Clamp(flex, prvSum, flexSum)
+	return flex + fixSum, true
+}
+
 func (sv *SplitView) Init2D() {
 	sv.Parts.Lay = LayoutNil
 	sv.Init2DWidget()
@@ -253,17 +604,12 @@ func (sv *SplitView) ConfigSplitters() {
 	size := sv.LayState.Alloc.Size.Dim(sv.Dim) - 2*spc
 	handsz := sv.HandleSize.Dots
 	mid := 0.5 * (sv.LayState.Alloc.Size.Dim(odim) - 2*spc)
-	spicon := IconName("")
-	if sv.Dim == mat32.X {
-		spicon = IconName("handle-circles-vert")
-	} else {
-		spicon = IconName("handle-circles-horiz")
-	}
 	for i, spk := range *sv.Parts.Children() {
 		sp := spk.(*Splitter)
 		sp.Defaults()
 		sp.SplitterNo = i
-		sp.Icon = spicon
+		sp.SetInactiveState(sv.IsSplitterFixed(i))
+		sp.Icon = sv.SplitterIcon(i)
 		sp.Dim = sv.Dim
 		sp.LayState.Alloc.Size.SetDim(sv.Dim, size)
 		sp.LayState.Alloc.Size.SetDim(odim, handsz*2)
@@ -340,6 +686,9 @@ func (sv *SplitView) StyleSplitView() {
 	sv.LayState.SetFromStyle(&sv.Sty.Layout) // also does reset
 	sv.HandleSize.SetFmInheritProp("handle-size", sv.This(), ki.NoInherit, ki.TypeProps)
 	sv.HandleSize.ToDots(&sv.Sty.UnContext)
+	for i := range sv.FixedSizes {
+		sv.FixedSizes[i].ToDots(&sv.Sty.UnContext)
+	}
 }
 
 func (sv *SplitView) Style2D() {
@@ -370,7 +719,51 @@ func (sv *SplitView) Layout2D(parBBox image.Rectangle, iter int) bool {
 	osz := sv.LayState.Alloc.Size.Dim(odim) - 2*spc
 	pos := float32(0.0)
 
-	spsum := float32(0)
+	// fixed-size children are removed from avail first, then the Need of
+	// each flexible non-collapsed child is reserved, and the remainder is
+	// distributed according to the splits -- if needs exceed avail, they are
+	// scaled down proportionally and children must scroll
+	flexAvail := avail
+	needs := make([]float32, sz)
+	needSum := float32(0)
+	flexSum := float32(0)
+	for i, sp := range sv.Splits {
+		if sv.IsFixed(i) {
+			if !sv.IsCollapsed(i) {
+				flexAvail -= sv.FixedSizes[i].Dots
+			}
+			continue
+		}
+		flexSum += sp
+		gis := sv.Kids[i].(Node2D).AsWidget()
+		if gis == nil || sv.IsCollapsed(i) {
+			continue
+		}
+		needs[i] = gis.LayState.Size.Need.Dim(sv.Dim)
+		needSum += needs[i]
+	}
+	if flexAvail < 0 {
+		flexAvail = 0
+	}
+	extra := flexAvail - needSum
+	if extra < 0 {
+		extra = 0
+		if needSum > 0 {
+			scale := flexAvail / needSum
+			for i := range needs {
+				needs[i] *= scale
+			}
+		}
+	}
+	kidx := sv.ResizeKeepIdx()
+	if kidx >= 0 && sv.PrevAvail > 0 && avail != sv.PrevAvail && extra > 0 {
+		sv.KeepSplitSize(kidx, sv.PrevKeep-needs[kidx], extra, flexSum)
+	}
+	sv.PrevAvail = avail
+	sv.NeedSizes = needs
+	sv.ExtraSize = extra
+
+	cumsz := float32(0)
 	for i, sp := range sv.Splits {
 		gis := sv.Kids[i].(Node2D).AsWidget()
 		if gis == nil {
@@ -379,7 +772,15 @@ func (sv *SplitView) Layout2D(parBBox image.Rectangle, iter int) bool {
 		if ki.TypeEmbeds(gis, KiT_Frame) {
 			gis.SetReRenderAnchor()
 		}
-		isz := sp * avail
+		isz := float32(0)
+		switch {
+		case sv.IsFixed(i):
+			if !sv.IsCollapsed(i) {
+				isz = sv.FixedSizes[i].Dots
+			}
+		case flexSum > 0:
+			isz = needs[i] + (sp/flexSum)*extra
+		}
 		gis.LayState.Alloc.Size.SetDim(sv.Dim, isz)
 		gis.LayState.Alloc.Size.SetDim(odim, osz)
 		gis.LayState.Alloc.SizeOrig = gis.LayState.Alloc.Size
@@ -388,11 +789,16 @@ func (sv *SplitView) Layout2D(parBBox image.Rectangle, iter int) bool {
 		// fmt.Printf("spl: %v sp: %v size: %v alloc: %v  pos: %v\n", i, sp, isz, gis.LayState.Alloc.SizeOrig, gis.LayState.Alloc.PosRel)
 
 		pos += isz + handsz
+		if i == kidx {
+			sv.PrevKeep = isz
+		}
 
-		spsum += sp
+		cumsz += isz
 		if i < sz-1 {
 			spl := sv.Parts.Child(i).(*Splitter)
-			spl.Value = spsum
+			if avail > 0 {
+				spl.Value = cumsz / avail
+			}
 			spl.UpdatePosFromValue()
 		}
 	}
@@ -400,6 +806,43 @@ func (sv *SplitView) Layout2D(parBBox image.Rectangle, iter int) bool {
 	return sv.Layout2DChildren(iter)
 }
 
+// ResizeKeepIdx returns the index of the element to keep at its prior size
+// according to ResizeMode, or -1 if none (or it is fixed or collapsed)
+func (sv *SplitView) ResizeKeepIdx() int {
+	idx := -1
+	switch sv.ResizeMode {
+	case SplitResizeKeepFirst:
+		idx = 0
+	case SplitResizeKeepLast:
+		idx = len(sv.Kids) - 1
+	}
+	if idx < 0 || idx >= len(sv.Splits) || sv.IsFixed(idx) || sv.IsCollapsed(idx) {
+		return -1
+	}
+	return idx
+}
+
+// KeepSplitSize updates the splits so that the element at given index gets
+// given size out of the extra space distributed according to the splits
+// (of which flexSum is the total), with other splits scaled to absorb the
+// difference
+func (sv *SplitView) KeepSplitSize(idx int, sz, extra, flexSum float32) {
+	nsp := flexSum * mat32.Clamp(sz/extra, 0, 1)
+	osp := sv.Splits[idx]
+	orest := flexSum - osp
+	if orest <= 0 {
+		return
+	}
+	scale := (flexSum - nsp) / orest
+	for i := range sv.Splits {
+		if i == idx {
+			sv.Splits[i] = nsp
+		} else if !sv.IsFixed(i) {
+			sv.Splits[i] *= scale
+		}
+	}
+}
+
 func (sv *SplitView) Render2D() {
 	if sv.FullReRenderIfNeeded() {
 		return
@@ -626,6 +1069,9 @@ func (sr *Splitter) MouseEvent() {
 			if me.Button == mouse.Left {
 				me.SetProcessed()
 				if me.Action == mouse.Press {
+					if sv := srr.SplitView(); sv != nil && sv.SplitterClick(srr.SplitterNo) {
+						return
+					}
 					ed := srr.This().(SliderPositioner).PointToRelPos(me.Where)
 					st := &srr.Sty
 					spc := st.Layout.Margin.Dots + 0.5*srr.ThSize
@@ -637,11 +1083,7 @@ func (sr *Splitter) MouseEvent() {
 				} else if me.Action == mouse.DoubleClick {
 					sv := srr.SplitView()
 					if sv != nil {
-						if sv.IsCollapsed(srr.SplitterNo) {
-							sv.RestoreSplits()
-						} else {
-							sv.CollapseChild(true, srr.SplitterNo)
-						}
+						sv.SplitterDoubleClick(srr.SplitterNo)
 					}
 				} else {
 					srr.SliderRelease()
@@ -0,0 +1,363 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"testing"
+
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// testSplitView returns a horizontal SplitView with n Frame panes, filling a
+// layout within a viewport of given size
+func testSplitView(width, height, n int) (*Viewport2D, *SplitView) {
+	vp := testViewport(width, height)
+	ly := AddNewLayout(vp, "ly", LayoutVert)
+	sv := AddNewSplitView(ly, "sv")
+	for i := 0; i < n; i++ {
+		AddNewFrame(sv, "pane", LayoutVert)
+	}
+	return vp, sv
+}
+
+// paneSize returns the allocated size of given pane along the split dim
+func paneSize(sv *SplitView, idx int) float32 {
+	return sv.Child(idx).(Node2D).AsWidget().LayState.Alloc.Size.Dim(sv.Dim)
+}
+
+func TestSplitViewHandleSize(t *testing.T) {
+	vp, sv := testSplitView(100, 100, 2)
+	vp.FullRender2DTree()
+	if sv.HandleSize.Dots != 10 {
+		t.Errorf("default handle size: %v, expected 10", sv.HandleSize.Dots)
+	}
+	sv.SetHandleSize(units.NewPx(20))
+	vp.FullRender2DTree()
+	for i := 0; i < 2; i++ {
+		if sz := paneSize(sv, i); sz != 40 {
+			t.Errorf("pane %d size: %v, expected 40", i, sz)
+		}
+	}
+}
+
+func TestSplitViewChildNeed(t *testing.T) {
+	vp := testViewport(200, 100)
+	ly := AddNewLayout(vp, "ly", LayoutVert)
+	sv := AddNewSplitView(ly, "sv")
+	addTestBox(sv, "small", 20, 10)
+	addTestBox(sv, "big", 60, 10)
+	vp.FullRender2DTree()
+	// avail = 200 - 10 handle = 190, needs = 80, extra = 110 split evenly
+	if sz := paneSize(sv, 0); sz != 75 {
+		t.Errorf("small pane size: %v, expected 75", sz)
+	}
+	if sz := paneSize(sv, 1); sz != 115 {
+		t.Errorf("big pane size: %v, expected 115", sz)
+	}
+
+	vp = testViewport(100, 100)
+	ly = AddNewLayout(vp, "ly", LayoutVert)
+	sv = AddNewSplitView(ly, "sv")
+	addTestBox(sv, "small", 60, 10)
+	addTestBox(sv, "big", 120, 10)
+	vp.FullRender2DTree()
+	// needs exceed avail of 90: scaled down proportionally
+	if sz := paneSize(sv, 0); sz != 30 {
+		t.Errorf("scaled small pane size: %v, expected 30", sz)
+	}
+	if sz := paneSize(sv, 1); sz != 60 {
+		t.Errorf("scaled big pane size: %v, expected 60", sz)
+	}
+}
+
+func TestSplitViewDragRoundTrip(t *testing.T) {
+	vp := testViewport(320, 100)
+	ly := AddNewLayout(vp, "ly", LayoutVert)
+	sv := AddNewSplitView(ly, "sv")
+	addTestBox(sv, "a", 20, 10)
+	addTestBox(sv, "b", 60, 10)
+	addTestBox(sv, "c", 40, 10)
+	vp.FullRender2DTree()
+	value := func(idx int) float32 {
+		return sv.Parts.Child(idx).(*Splitter).Value
+	}
+	// releasing a splitter where it already is does not move it
+	for i := 0; i < 2; i++ {
+		v := value(i)
+		sv.SetSplitAction(i, v)
+		vp.FullRender2DTree()
+		if nv := value(i); mat32.Abs(nv-v) > 0.001 {
+			t.Errorf("splitter %d at: %v after release at: %v", i, nv, v)
+		}
+	}
+	// and it lands where it is released
+	for i, v := range []float32{0.3, 0.8} {
+		sv.SetSplitAction(i, v)
+		vp.FullRender2DTree()
+		if nv := value(i); mat32.Abs(nv-v) > 0.001 {
+			t.Errorf("splitter %d at: %v after release at: %v", i, nv, v)
+		}
+	}
+}
+
+func TestSplitViewFixedSizes(t *testing.T) {
+	vp, sv := testSplitView(650, 100, 3)
+	sv.SetHandleSize(units.NewPx(0))
+	sv.SetFixedSizes(units.NewPx(250))
+	vp.FullRender2DTree()
+	exp := []float32{250, 200, 200}
+	for i, ex := range exp {
+		if sz := paneSize(sv, i); sz != ex {
+			t.Errorf("pane %d size: %v, expected %v", i, sz, ex)
+		}
+	}
+
+	// fixed pane keeps its size on resize
+	vp.Resize(image.Point{450, 100})
+	vp.FullRender2DTree()
+	exp = []float32{250, 100, 100}
+	for i, ex := range exp {
+		if sz := paneSize(sv, i); sz != ex {
+			t.Errorf("resized pane %d size: %v, expected %v", i, sz, ex)
+		}
+	}
+}
+
+func TestSplitViewFixedDrag(t *testing.T) {
+	vp, sv := testSplitView(650, 100, 3)
+	sv.SetHandleSize(units.NewPx(0))
+	sv.SetFixedSizes(units.NewPx(250))
+	vp.FullRender2DTree()
+	spl := sv.Parts.Child(0).(*Splitter)
+	if !spl.IsInactive() || sv.Parts.Child(1).(*Splitter).IsInactive() {
+		t.Errorf("only the splitter after the fixed pane should be inactive")
+	}
+	// dragging next to the fixed pane does nothing
+	sv.SetSplitAction(0, 0.6)
+	vp.FullRender2DTree()
+	for i, ex := range []float32{250, 200, 200} {
+		if sz := paneSize(sv, i); sz != ex {
+			t.Errorf("pane %d size: %v, expected %v", i, sz, ex)
+		}
+	}
+	if v := spl.Value; mat32.Abs(v-250.0/650) > 0.001 {
+		t.Errorf("fixed splitter at: %v, expected to stay at the fixed size", v)
+	}
+	// dragging between the flexible panes leaves the fixed one as is
+	sv.SetSplitAction(1, 0.6)
+	vp.FullRender2DTree()
+	for i, ex := range []float32{250, 140, 260} {
+		if sz := paneSize(sv, i); mat32.Abs(sz-ex) > 0.01 {
+			t.Errorf("pane %d size after drag: %v, expected %v", i, sz, ex)
+		}
+	}
+}
+
+func TestSplitViewBatchSplits(t *testing.T) {
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(1, 1, 2)
+	sv.SetSplits(3, 1, 4)
+	exp := make([]float32, len(sv.Splits))
+	copy(exp, sv.Splits)
+
+	vp, sv = testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	nupdt := 0
+	sv.NodeSignal().Connect(vp.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(ki.NodeSignalUpdated) {
+			nupdt++
+		}
+	})
+	sv.SetSplitsNoUpdate(1, 1, 2)
+	sv.SetSplitsNoUpdate(3, 1, 4)
+	sv.UpdateNow()
+	if nupdt != 1 {
+		t.Errorf("number of updates: %v, expected 1", nupdt)
+	}
+	for i, ex := range exp {
+		if sv.Splits[i] != ex {
+			t.Errorf("split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+}
+
+func TestSplitViewResizeKeepFirst(t *testing.T) {
+	vp, sv := testSplitView(200, 100, 2)
+	sv.ResizeMode = SplitResizeKeepFirst
+	vp.FullRender2DTree()
+	first := paneSize(sv, 0)
+	if first != 95 {
+		t.Errorf("initial first pane size: %v, expected 95", first)
+	}
+
+	vp.Resize(image.Point{300, 100})
+	vp.FullRender2DTree()
+	if sz := paneSize(sv, 0); mat32.Abs(sz-first) > 0.01 {
+		t.Errorf("first pane size after resize: %v, expected kept at %v", sz, first)
+	}
+	if sz := paneSize(sv, 1); mat32.Abs(sz-195) > 0.01 {
+		t.Errorf("last pane size after resize: %v, expected 195", sz)
+	}
+}
+
+func TestSplitViewMaximizeChild(t *testing.T) {
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(0.2, 0.3, 0.5)
+	prv := make([]float32, len(sv.Splits))
+	copy(prv, sv.Splits)
+
+	sv.MaximizeChild(1)
+	if !sv.IsMaximized(1) || !sv.IsCollapsed(0) || !sv.IsCollapsed(2) {
+		t.Errorf("maximized splits: %v", sv.Splits)
+	}
+	sv.RestoreSplits()
+	for i, ex := range prv {
+		if sv.Splits[i] != ex {
+			t.Errorf("restored split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+
+	// second call toggles back
+	sv.MaximizeChild(2)
+	sv.MaximizeChild(2)
+	for i, ex := range prv {
+		if sv.Splits[i] != ex {
+			t.Errorf("toggled split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+}
+
+func TestSplitViewResetSplits(t *testing.T) {
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(0.2, 0.3, 0.5)
+	prv := make([]float32, len(sv.Splits))
+	copy(prv, sv.Splits)
+
+	sv.ResetSplits()
+	if !sv.IsEven() {
+		t.Errorf("reset splits not even: %v", sv.Splits)
+	}
+	sv.ResetSplits()
+	for i, ex := range prv {
+		if sv.Splits[i] != ex {
+			t.Errorf("toggled split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+}
+
+func TestSplitViewDoubleClick(t *testing.T) {
+	tests := []struct {
+		act SplitDblClicks
+		exp []float32
+	}{
+		{SplitDblClickCollapse, []float32{0.25, 0, 0.75}},
+		{SplitDblClickEqualize, []float32{0.2, 0.4, 0.4}},
+		{SplitDblClickRestore, []float32{0.2, 0.3, 0.5}},
+	}
+	for _, ts := range tests {
+		vp, sv := testSplitView(300, 100, 3)
+		vp.FullRender2DTree()
+		sv.DoubleClick = ts.act
+		sv.SetSplits(0.2, 0.3, 0.5)
+		sv.SaveSplits()
+		sv.SetSplits(0.2, 0.2, 0.6)
+		sv.SplitterDoubleClick(1)
+		for i, ex := range ts.exp {
+			if mat32.Abs(sv.Splits[i]-ex) > 0.0001 {
+				t.Errorf("%v: split %d: %v, expected %v", ts.act, i, sv.Splits[i], ex)
+			}
+		}
+	}
+
+	// collapse toggles back to the saved splits
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(0.2, 0.3, 0.5)
+	sv.SplitterDoubleClick(1)
+	sv.SplitterDoubleClick(1)
+	for i, ex := range []float32{0.2, 0.3, 0.5} {
+		if mat32.Abs(sv.Splits[i]-ex) > 0.0001 {
+			t.Errorf("collapse toggle: split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+}
+
+func TestSplitViewBadSplits(t *testing.T) {
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(mat32.NaN(), 1, mat32.Inf(1))
+	for i, ex := range []float32{0, 1, 0} {
+		if sv.Splits[i] != ex {
+			t.Errorf("split %d: %v, expected %v", i, sv.Splits[i], ex)
+		}
+	}
+
+	// nothing valid left: even splits
+	sv.SetSplits(mat32.NaN(), -1, mat32.Inf(-1))
+	for i, sp := range sv.Splits {
+		if mat32.Abs(sp-1.0/3.0) > 0.0001 {
+			t.Errorf("split %d: %v, expected even split", i, sp)
+		}
+	}
+}
+
+func TestSplitViewExpandCollapsed(t *testing.T) {
+	vp, sv := testSplitView(300, 100, 3)
+	vp.FullRender2DTree()
+	sv.SetSplits(0.2, 0.3, 0.5)
+	sv.CollapseChild(true, 0)
+	vp.FullRender2DTree()
+
+	sp := sv.Parts.Child(0).(*Splitter)
+	if sp.Icon != "wedge-right" {
+		t.Errorf("collapsed pane handle icon: %v, expected wedge-right", sp.Icon)
+	}
+	if sv.SplitterClick(1) {
+		t.Errorf("click on handle without collapsed pane should not expand")
+	}
+	if !sv.SplitterClick(0) {
+		t.Fatalf("click on collapsed pane handle should expand")
+	}
+	if sv.Splits[0] <= 0 {
+		t.Errorf("expanded split: %v, expected nonzero", sv.Splits[0])
+	}
+	for i, ex := range []float32{0.2, 0.3, 0.5} {
+		if mat32.Abs(sv.Splits[i]-ex) > 0.0001 {
+			t.Errorf("split %d: %v, expected saved %v", i, sv.Splits[i], ex)
+		}
+	}
+	vp.FullRender2DTree()
+	if sp.Icon != "handle-circles-vert" {
+		t.Errorf("expanded pane handle icon: %v, expected handle", sp.Icon)
+	}
+}
+
+func TestSplitViewSetDim(t *testing.T) {
+	vp, sv := testSplitView(200, 200, 2)
+	vp.FullRender2DTree()
+	p0 := sv.Child(0).(Node2D).AsWidget()
+	p1 := sv.Child(1).(Node2D).AsWidget()
+	if p1.LayState.Alloc.PosRel.X <= p0.LayState.Alloc.PosRel.X {
+		t.Fatalf("horizontal panes at: %v, %v, expected side by side", p0.LayState.Alloc.PosRel, p1.LayState.Alloc.PosRel)
+	}
+	sv.SetDim(mat32.Y)
+	vp.FullRender2DTree()
+	a0, a1 := p0.LayState.Alloc, p1.LayState.Alloc
+	if a0.PosRel.X != a1.PosRel.X || a1.PosRel.Y < a0.PosRel.Y+a0.Size.Y {
+		t.Errorf("vertical panes at: %v, %v, size: %v, expected stacked", a0.PosRel, a1.PosRel, a0.Size)
+	}
+	if a0.Size.Y <= 0 || a0.Size.Y != a1.Size.Y {
+		t.Errorf("vertical pane heights: %v, %v, expected equal", a0.Size.Y, a1.Size.Y)
+	}
+	if spl := sv.Parts.Child(0).(*Splitter); spl.Dim != mat32.Y {
+		t.Errorf("splitter dim: %v, expected Y", spl.Dim)
+	}
+}
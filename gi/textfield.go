@@ -1505,7 +1505,8 @@ func (tf *TextField) RenderTextField() {
 	} else {
 		tf.Sty = tf.StateStyles[TextFieldActive]
 	}
-	st = &tf.Sty // update
+	tf.LayState.BoxSpcOk = false // style replaced
+	st = &tf.Sty                 // update
 	girl.OpenFont(&st.Font, &st.UnContext)
 	tf.RenderStdBox(st)
 	cur := tf.EditTxt[tf.StartPos:tf.EndPos]
@@ -390,10 +390,7 @@ func (vp *Viewport2D) ReRender2DNode(gni Node2D) {
 	// pr := prof.Start("vp.ReRender2DNode")
 	gn.Render2DTree()
 	// pr.End()
-	gn.BBoxMu.RLock()
-	wbb := gn.WinBBox
-	gn.BBoxMu.RUnlock()
-	vp.This().(Viewport).VpUploadRegion(gn.VpBBox, wbb)
+	vp.This().(Viewport).VpUploadRegion(ReRender2DBBoxes(gni))
 }
 
 // ReRender2DAnchor re-renders an anchor node -- the KEY diff from
@@ -415,10 +412,29 @@ func (vp *Viewport2D) ReRender2DAnchor(gni Node2D) {
 	// pr := prof.Start("vp.ReRender2DNode")
 	pw.ReRender2DTree()
 	// pr.End()
-	pw.BBoxMu.RLock()
-	wbb := pw.WinBBox
-	pw.BBoxMu.RUnlock()
-	vp.This().(Viewport).VpUploadRegion(pw.VpBBox, wbb)
+	vp.This().(Viewport).VpUploadRegion(ReRender2DBBoxes(gni))
+}
+
+// ReRender2DBBoxes returns the viewport and window bounding boxes of the
+// region drawn when re-rendering given node: its VpBBox, extended by the
+// box shadow of a Shadower (e.g., Frame), which is drawn outside of it.
+func ReRender2DBBoxes(gni Node2D) (vpBBox, winBBox image.Rectangle) {
+	gn := gni.AsNode2D()
+	gn.BBoxMu.RLock()
+	vpBBox, winBBox = gn.VpBBox, gn.WinBBox
+	gn.BBoxMu.RUnlock()
+	sh, ok := gni.(Shadower)
+	if !ok {
+		return
+	}
+	sbb := sh.ShadowBBox2D()
+	if sbb.Empty() {
+		return
+	}
+	winrel := winBBox.Min.Sub(vpBBox.Min)
+	vpBBox = vpBBox.Union(sbb)
+	winBBox = vpBBox.Add(winrel)
+	return
 }
 
 // Delete this popup viewport -- has already been disconnected from window
@@ -571,6 +587,9 @@ func (vp *Viewport2D) FullRender2DTree() {
 		return
 	}
 	vp.SetFlag(int(VpFlagDoingFullRender))
+	if LayoutProfile && vp.Viewport == nil { // new frame for top-level viewport
+		LayoutProf.Reset()
+	}
 	if Render2DTrace {
 		fmt.Printf("Render: %v doing full render\n", vp.Path())
 	}
@@ -82,14 +82,47 @@ func (wb *WidgetBase) StyleRUnlock() {
 	wb.StyMu.RUnlock()
 }
 
-// BoxSpace returns the style BoxSpace value under read lock
+// BoxSpace returns the style BoxSpace value under read lock -- the value is
+// cached in LayState for the duration of a layout pass, and recomputed
+// after the style is updated or replaced (e.g., by a state style).  The
+// cache is written under the write lock, so this must not be called with
+// StyMu locked -- use Sty.BoxSpace() directly then.
 func (wb *WidgetBase) BoxSpace() float32 {
 	wb.StyMu.RLock()
-	bs := wb.Sty.BoxSpace()
+	bs, ok := wb.LayState.BoxSpc, wb.LayState.BoxSpcOk
+	if !ok {
+		bs = wb.Sty.BoxSpace()
+	}
 	wb.StyMu.RUnlock()
+	if !ok {
+		wb.StyMu.Lock()
+		wb.LayState.BoxSpc = bs
+		wb.LayState.BoxSpcOk = true
+		wb.StyMu.Unlock()
+	}
 	return bs
 }
 
+// IsCollapsed returns true if the widget is collapsed out of the layout by
+// the display: none style property -- it then takes up no space in its
+// parent layout and is not rendered.
+func (wb *WidgetBase) IsCollapsed() bool {
+	wb.StyMu.RLock()
+	col := !wb.Sty.Display
+	wb.StyMu.RUnlock()
+	return col
+}
+
+// IsHidden returns true if the widget is hidden by the visibility: hidden
+// style property -- it still takes up its space in the layout, but is not
+// rendered and does not receive events.
+func (wb *WidgetBase) IsHidden() bool {
+	wb.StyMu.RLock()
+	hid := wb.Sty.Hidden
+	wb.StyMu.RUnlock()
+	return hid
+}
+
 // Init2DWidget handles basic node initialization -- Init2D can then do special things
 func (wb *WidgetBase) Init2DWidget() {
 	wb.BBoxMu.Lock()
@@ -219,12 +252,17 @@ func (wb *WidgetBase) Style2DWidget() {
 	AggCSS(&wb.CSSAgg, wb.CSS)
 	StyleCSS(gii, wb.Viewport, &wb.Sty, wb.CSSAgg, "")
 
-	SetUnitContext(&wb.Sty, wb.Viewport, mat32.Vec2Zero) // todo: test for use of el-relative
-	if wb.Sty.Inactive {                                 // inactive can only set, not clear
+	// the element-relative units (percentages) are relative to the parent
+	// size from the last layout, as in Layout2DBase, so they resolve the same
+	// way in both -- the other units do not depend on it, and it is zero
+	// (leaving the context as is) before the first layout
+	SetUnitContext(&wb.Sty, wb.Viewport, wb.LayState.ParSize)
+	if wb.Sty.Inactive { // inactive can only set, not clear
 		wb.SetInactive()
 	}
 
 	wb.Viewport.SetCurrentColor(wb.Sty.Font.Color)
+	wb.LayState.BoxSpcOk = false // style updated
 }
 
 // StylePart sets the style properties for a child in parts (or any other
@@ -334,10 +372,13 @@ func SetUnitContext(st *gist.Style, vp *Viewport2D, el mat32.Vec2) {
 		if vp.Win != nil {
 			st.UnContext.DPI = vp.Win.LogicalDPI()
 		}
-		if vp.Render.Image != nil {
-			sz := vp.Geom.Size // Render.Image.Bounds().Size()
-			st.UnContext.SetSizes(float32(sz.X), float32(sz.Y), el.X, el.Y)
+		// viewport-relative units (vw, vh etc) are relative to the enclosing
+		// viewport's size -- use the geometry even if not yet rendered
+		sz := vp.Geom.Size // Render.Image.Bounds().Size()
+		if sz == image.ZP && vp.Render.Image != nil {
+			sz = vp.Render.Image.Bounds().Size()
 		}
+		st.UnContext.SetSizes(float32(sz.X), float32(sz.Y), el.X, el.Y)
 	}
 	girl.OpenFont(&st.Font, &st.UnContext) // calls SetUnContext after updating metrics
 	st.ToDots()
@@ -356,6 +397,36 @@ func (wb *WidgetBase) Size2DBase(iter int) {
 
 func (wb *WidgetBase) Size2D(iter int) {
 	wb.Size2DBase(iter)
+	wb.Size2DAddMargin()
+}
+
+// Size2DAddMargin adds the margin around the Need and Pref sizes from the
+// style, for widgets that are sized only by their style (e.g., Space), so
+// that the margin is outside of the given size and separates the widget
+// from its neighbors, as it does for widgets sized from their content (see
+// Size2DFromWH) -- the margin is within the allocation, inset by rendering,
+// so it is likewise added to the Max size, so it is not clamped away.
+// A negative margin reduces the sizes (down to 0), so that the box extends
+// beyond the allocation and overlaps its neighbors.
+func (wb *WidgetBase) Size2DAddMargin() {
+	wb.StyMu.RLock()
+	marg := 2 * wb.Sty.Layout.Margin.Dots
+	wb.StyMu.RUnlock()
+	if marg == 0 {
+		return
+	}
+	sz := &wb.LayState.Size
+	for d := mat32.X; d <= mat32.Y; d++ {
+		if nd := sz.Need.Dim(d); nd > 0 {
+			sz.Need.SetDim(d, mat32.Max(nd+marg, 0))
+		}
+		if pr := sz.Pref.Dim(d); pr > 0 {
+			sz.Pref.SetDim(d, mat32.Max(pr+marg, 0))
+		}
+		if mx := sz.Max.Dim(d); mx > 0 && mx+marg > 0 {
+			sz.Max.SetDim(d, mx+marg)
+		}
+	}
 }
 
 // AddParentPos adds the position of our parent to our layout position --
@@ -374,9 +445,16 @@ func (wb *WidgetBase) AddParentPos() mat32.Vec2 {
 	return mat32.Vec2Zero
 }
 
-// BBoxFromAlloc gets our bbox from Layout allocation.
+// BBoxFromAlloc gets our bbox from Layout allocation -- extended beyond it
+// by a negative margin, which the allocation is reduced by (see
+// Size2DAddMargin), so the box overlaps its neighbors.
 func (wb *WidgetBase) BBoxFromAlloc() image.Rectangle {
-	return mat32.RectFromPosSizeMax(wb.LayState.Alloc.Pos, wb.LayState.Alloc.Size)
+	pos, sz := wb.LayState.Alloc.Pos, wb.LayState.Alloc.Size
+	if mrg := wb.Sty.Layout.Margin.Dots; mrg < 0 {
+		pos = pos.AddScalar(mrg)
+		sz = sz.SubScalar(2 * mrg)
+	}
+	return LayoutRect(pos, sz)
 }
 
 func (wb *WidgetBase) BBox2D() image.Rectangle {
@@ -402,16 +480,20 @@ func (wb *WidgetBase) Layout2DBase(parBBox image.Rectangle, initStyle bool, iter
 		}
 	}
 	psize := wb.AddParentPos()
+	wb.LayState.ParSize = psize
 	wb.LayState.Alloc.PosOrig = wb.LayState.Alloc.Pos
 	if initStyle {
 		mvp := wb.ViewportSafe()
+		ls := &wb.Sty.Layout
+		szs := [3]mat32.Vec2{ls.SizeDots(), ls.MinSizeDots(), ls.MaxSizeDots()}
 		SetUnitContext(&wb.Sty, mvp, psize) // update units with final layout
+		wb.LayState.UnitsChanged = szs != [3]mat32.Vec2{ls.SizeDots(), ls.MinSizeDots(), ls.MaxSizeDots()}
 	}
 	wb.BBox = nii.BBox2D() // only compute once, at this point
 	// note: if other styles are maintained, they also need to be updated!
 	nii.ComputeBBox2D(parBBox, image.ZP) // other bboxes from BBox
-	if Layout2DTrace {
-		fmt.Printf("Layout: %v alloc pos: %v size: %v vpbb: %v winbb: %v\n", wb.Path(), wb.LayState.Alloc.Pos, wb.LayState.Alloc.Size, wb.VpBBox, wb.WinBBox)
+	if wb.LayoutTraceOn() {
+		Layout2DTracef("Layout: %v alloc pos: %v size: %v vpbb: %v winbb: %v\n", wb.Path(), wb.LayState.Alloc.Pos, wb.LayState.Alloc.Size, wb.VpBBox, wb.WinBBox)
 	}
 	// typically Layout2DChildren must be called after this!
 }
@@ -422,15 +504,38 @@ func (wb *WidgetBase) Layout2D(parBBox image.Rectangle, iter int) bool {
 }
 
 // ChildrenBBox2DWidget provides a basic widget box-model subtraction of
-// margin and padding to children -- call in ChildrenBBox2D for most widgets
+// margin and padding to children -- call in ChildrenBBox2D for most widgets.
+// The box space is removed from the object box (ChildrenObjBBox) and then
+// limited to the visible VpBBox, so that we clip at our own box even when an
+// ancestor clips part of us away, e.g., when scrolled partly out of view.
 func (wb *WidgetBase) ChildrenBBox2DWidget() image.Rectangle {
-	nb := wb.VpBBox
-	spc := int(wb.BoxSpace())
-	nb.Min.X += spc
-	nb.Min.Y += spc
-	nb.Max.X -= spc
-	nb.Max.Y -= spc
-	return nb
+	return wb.ChildrenObjBBox().Intersect(wb.VpBBox)
+}
+
+// ChildrenObjBBox returns the full box of our children, not clipped by our
+// parents: the ObjBBox within the box space (margin, border, padding).
+// When our BBox comes from the layout allocation, the content edges in dots
+// are rounded inward (ceil of the min, floor of the max), so that rounding of
+// a fractional allocation never extends the box outside the content area.
+func (wb *WidgetBase) ChildrenObjBBox() image.Rectangle {
+	nb := wb.ObjBBox
+	spc := wb.BoxSpace()
+	ispc := LayoutRoundDots(spc)
+	nb.Min.X += ispc
+	nb.Min.Y += ispc
+	nb.Max.X -= ispc
+	nb.Max.Y -= ispc
+	if wb.BBox != wb.BBoxFromAlloc() {
+		return nb
+	}
+	pos := wb.LayState.Alloc.Pos
+	end := pos.Add(wb.LayState.Alloc.Size)
+	cb := image.Rectangle{
+		Min: image.Point{int(mat32.Ceil(pos.X + spc)), int(mat32.Ceil(pos.Y + spc))},
+		Max: image.Point{int(mat32.Floor(end.X - spc)), int(mat32.Floor(end.Y - spc))},
+	}
+	cb = cb.Add(wb.ObjBBox.Min.Sub(wb.BBox.Min)) // scrolling offset from ComputeBBox2D
+	return nb.Intersect(cb)
 }
 
 func (wb *WidgetBase) ChildrenBBox2D() image.Rectangle {
@@ -527,7 +632,7 @@ func (wb *WidgetBase) ReRender2DTree() {
 	wb.LayState = ld // restore
 	wb.Layout2DTree()
 	if !delta.IsNil() {
-		wb.Move2D(delta.ToPointFloor(), parBBox)
+		wb.Move2D(LayoutRoundPoint(delta), parBBox)
 	}
 	wb.Render2DTree()
 	wb.UpdateEndNoSig(updt)
@@ -556,7 +661,11 @@ func (wb *WidgetBase) Move2DTree() {
 	if pn != nil {
 		parBBox = pnii.ChildrenBBox2D()
 	}
-	delta := wb.LayState.Alloc.Pos.Sub(wb.LayState.Alloc.PosOrig).ToPoint()
+	dpos := wb.LayState.Alloc.Pos.Sub(wb.LayState.Alloc.PosOrig)
+	delta := dpos.ToPoint() // default truncates, as always
+	if LayoutRounding != RoundPosSize {
+		delta = LayoutRoundPoint(dpos)
+	}
 	wb.This().(Node2D).Move2D(delta, parBBox) // important to use interface version to get interface!
 }
 
@@ -877,8 +986,8 @@ func (wb *PartsWidgetBase) CopyFieldsFrom(frm interface{}) {
 func (wb *PartsWidgetBase) SizeFromParts(iter int) {
 	wb.LayState.Alloc.Size = wb.Parts.LayState.Size.Pref // get from parts
 	wb.Size2DAddSpace()
-	if Layout2DTrace {
-		fmt.Printf("Size:   %v size from parts: %v, parts pref: %v\n", wb.Path(), wb.LayState.Alloc.Size, wb.Parts.LayState.Size.Pref)
+	if wb.LayoutTraceOn() {
+		Layout2DTracef("Size:   %v size from parts: %v, parts pref: %v\n", wb.Path(), wb.LayState.Alloc.Size, wb.Parts.LayState.Size.Pref)
 	}
 }
 
@@ -51,11 +51,23 @@ type Layout struct {
 	Padding        units.Value `xml:"padding" desc:"prop: padding = transparent space around central content of box -- todo: if 4 values it is top, right, bottom, left; 3 is top, right&left, bottom; 2 is top & bottom, right and left"`
 	Overflow       Overflow    `xml:"overflow" desc:"prop: overflow = what to do with content that overflows -- default is Auto add of scrollbars as needed -- todo: can have separate -x -y values"`
 	Columns        int         `xml:"columns" alt:"grid-cols" desc:"prop: columns = number of columns to use in a grid layout -- used as a constraint in layout if individual elements do not specify their row, column positions"`
-	Row            int         `xml:"row" desc:"prop: row = specifies the row that this element should appear within a grid layout"`
-	Col            int         `xml:"col" desc:"prop: col = specifies the column that this element should appear within a grid layout"`
+	Rows           int         `xml:"rows" alt:"grid-rows" desc:"prop: rows = number of explicit rows in a grid layout -- any additional rows needed to hold all the elements are implicit rows, sized according to grid-auto-rows"`
+	GridAutoRows   units.Value `xml:"grid-auto-rows" desc:"prop: grid-auto-rows = size of implicit rows in a grid layout, beyond the explicit rows -- 0 means size to the content, as for explicit rows"`
+	GridAutoCols   units.Value `xml:"grid-auto-cols" desc:"prop: grid-auto-cols = size of implicit columns in a grid layout, beyond the explicit columns -- 0 means size to the content, as for explicit columns"`
+	Row            int         `xml:"row" desc:"prop: row = specifies the row that this element should appear within a grid layout -- 0 = auto-placed, following the previous child, so the first row can only be set explicitly with grid-row-start: 1 (grid lines are 1-based)"`
+	Col            int         `xml:"col" desc:"prop: col = specifies the column that this element should appear within a grid layout -- 0 = auto-placed, following the previous child, so the first column can only be set explicitly with grid-column-start: 1 (grid lines are 1-based)"`
 	RowSpan        int         `xml:"row-span" desc:"prop: row-span = specifies the number of sequential rows that this element should occupy within a grid layout (todo: not currently supported)"`
 	ColSpan        int         `xml:"col-span" desc:"prop: col-span = specifies the number of sequential columns that this element should occupy within a grid layout"`
+	GridColStart   int         `xml:"grid-column-start" desc:"prop: grid-column-start = 1-based grid line at which this element starts within a grid layout, with negative numbers counting back from the end line (-1 = the last line) -- 0 = unset -- overrides col -- the grid-column property sets both start and end as start / end, e.g., 1 / -1 to span all columns"`
+	GridRowStart   int         `xml:"grid-row-start" desc:"prop: grid-row-start = 1-based grid line at which this element starts within a grid layout, with negative numbers counting back from the end line (-1 = the last line) -- 0 = unset -- overrides row"`
+	GridColEnd     int         `xml:"grid-column-end" desc:"prop: grid-column-end = 1-based grid line at which this element ends within a grid layout, with negative numbers counting back from the end line (-1 = the last line) -- 0 = unset -- overrides col-span"`
+	GridArea       string      `xml:"grid-area" desc:"prop: grid-area = name of the area of the grid-template-areas of a grid layout in which this element is placed, spanning all of its rows and columns -- overrides row, col and the spans"`
 	ScrollBarWidth units.Value `xml:"scrollbar-width" desc:"prop: scrollbar-width = width of a layout scrollbar"`
+	Order          int         `xml:"order" desc:"prop: order = ordering factor for the visual position of the element within a row or column layout -- elements are sorted by order (stably, so equal values keep the tree order) for positioning and rendering, without changing the actual order of the children, as in the CSS flexbox order property"`
+	Region         Region      `xml:"region" desc:"prop: region = region of a border layout in which the element is placed: north and south span the top and bottom edges, west and east the left and right edges between them, and center gets the rest of the space"`
+	AspectRatio    float32     `xml:"aspect-ratio" desc:"prop: aspect-ratio = ratio of width to height to maintain within the size allocated by the layout -- the height is computed from the allocated width, unless that would exceed the max-height or the height allocated by the layout, in which case the width is reduced instead -- 0 means no constraint"`
+	FlexGrow       float32     `xml:"flex-grow" desc:"prop: flex-grow = factor for growing the element beyond its preferred size along a row or column layout, when there is extra space: the extra is shared in proportion to this factor among the stretchy and growing elements, and the element grows only up to its max-width / max-height, if set -- 0 = only grows if stretchy, as in the CSS flex-grow property"`
+	FlexShrink     float32     `xml:"flex-shrink" desc:"prop: flex-shrink = factor for shrinking the element below its preferred size, down to its min size, along a row or column layout without room for the preferred sizes: if any element sets it, the missing space is taken from such elements in proportion to this factor times their preferred size, and the others keep their preferred sizes, as in the CSS flex-shrink property -- 0 = does not shrink"`
 }
 
 func (ls *Layout) Defaults() {
@@ -111,6 +123,8 @@ func (ly *Layout) ToDots(uc *units.Context) {
 	ly.Margin.ToDots(uc)
 	ly.Padding.ToDots(uc)
 	ly.ScrollBarWidth.ToDots(uc)
+	ly.GridAutoRows.ToDots(uc)
+	ly.GridAutoCols.ToDots(uc)
 }
 
 // Align has all different types of alignment -- only some are applicable to
@@ -191,6 +205,11 @@ const (
 	// OverflowHidden hides the overflow and doesn't present scrollbars (supported).
 	OverflowHidden
 
+	// OverflowFade clips the overflow and draws a gradient fade at the edges
+	// where there is more content, instead of presenting scrollbars --
+	// scrolling is still possible via the mouse wheel.
+	OverflowFade
+
 	OverflowN
 )
 
@@ -201,6 +220,38 @@ func (ev *Overflow) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON
 
 //go:generate stringer -type=Overflow
 
+// Region is the region of a border layout in which an element is placed
+type Region int32
+
+const (
+	// RegionCenter gets all the space remaining after the edge regions,
+	// and is where the main, scrollable, content goes
+	RegionCenter Region = iota
+
+	// RegionNorth spans the top edge, at its preferred height
+	RegionNorth
+
+	// RegionSouth spans the bottom edge, at its preferred height
+	RegionSouth
+
+	// RegionEast is on the right edge, between north and south, at its
+	// preferred width
+	RegionEast
+
+	// RegionWest is on the left edge, between north and south, at its
+	// preferred width
+	RegionWest
+
+	RegionN
+)
+
+var KiT_Region = kit.Enums.AddEnumAltLower(RegionN, kit.NotBitFlag, StylePropProps, "Region")
+
+func (ev Region) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *Region) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+//go:generate stringer -type=Region
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // Layout Data for actually computing the layout
 
@@ -17,12 +17,13 @@ func _() {
 	_ = x[OverflowScroll-1]
 	_ = x[OverflowVisible-2]
 	_ = x[OverflowHidden-3]
-	_ = x[OverflowN-4]
+	_ = x[OverflowFade-4]
+	_ = x[OverflowN-5]
 }
 
-const _Overflow_name = "OverflowAutoOverflowScrollOverflowVisibleOverflowHiddenOverflowN"
+const _Overflow_name = "OverflowAutoOverflowScrollOverflowVisibleOverflowHiddenOverflowFadeOverflowN"
 
-var _Overflow_index = [...]uint8{0, 12, 26, 41, 55, 64}
+var _Overflow_index = [...]uint8{0, 12, 26, 41, 55, 67, 76}
 
 func (i Overflow) String() string {
 	if i < 0 || i >= Overflow(len(_Overflow_index)-1) {
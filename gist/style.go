@@ -40,6 +40,7 @@ type Style struct {
 	Template      string        `desc:"if present, then this should use unique template name for cached style -- critical for large numbers of repeated widgets in e.g., sliceview, tableview, etc"`
 	Display       bool          `xml:"display" desc:"todo big enum of how to display item -- controls layout etc"`
 	Visible       bool          `xml:"visible" desc:"is the item visible or not"`
+	Hidden        bool          `xml:"visibility" desc:"prop: visibility = hidden makes the item invisible, but it still takes up its space in the layout -- in contrast to display: none"`
 	Inactive      bool          `xml:"inactive" desc:"make a control inactive so it does not respond to input"`
 	Layout        Layout        `desc:"layout styles -- do not prefix with any xml"`
 	Border        Border        `xml:"border" desc:"border around the box element -- todo: can have separate ones for different sides"`
@@ -79,8 +80,6 @@ func (s *Style) Defaults() {
 
 // Object-fit for videos
 
-// visibility -- support more than just hidden  inherit:"true"
-
 // transition -- animation of hover, etc
 
 // RebuildDefaultStyles is a global state var used by Prefs to trigger rebuild
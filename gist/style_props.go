@@ -6,6 +6,7 @@ package gist
 
 import (
 	"log"
+	"strings"
 
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
@@ -120,10 +121,27 @@ var StyleStyleFuncs = map[string]StyleFunc{
 		}
 		if kit.ToString(val) == "none" {
 			s.Display = false
-		} else {
-			if bv, ok := kit.ToBool(val); ok {
-				s.Display = bv
+		} else if bv, ok := kit.ToBool(val); ok {
+			s.Display = bv
+		} else { // block, flex, etc all restore display
+			s.Display = true
+		}
+	},
+	"visibility": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		s := obj.(*Style)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				s.Hidden = par.(*Style).Hidden
+			} else if init {
+				s.Hidden = false
 			}
+			return
+		}
+		switch kit.ToString(val) {
+		case "hidden", "collapse":
+			s.Hidden = true
+		default: // visible
+			s.Hidden = false
 		}
 	},
 	"visible": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
@@ -392,6 +410,44 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 			ly.Columns = int(iv)
 		}
 	},
+	"rows": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.Rows = par.(*Layout).Rows
+			} else if init {
+				ly.Rows = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.Rows = int(iv)
+		}
+	},
+	"grid-auto-rows": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridAutoRows = par.(*Layout).GridAutoRows
+			} else if init {
+				ly.GridAutoRows.Val = 0
+			}
+			return
+		}
+		ly.GridAutoRows.SetIFace(val, key)
+	},
+	"grid-auto-cols": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridAutoCols = par.(*Layout).GridAutoCols
+			} else if init {
+				ly.GridAutoCols.Val = 0
+			}
+			return
+		}
+		ly.GridAutoCols.SetIFace(val, key)
+	},
 	"row": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
 		ly := obj.(*Layout)
 		if inh, init := StyleInhInit(val, par); inh || init {
@@ -448,6 +504,95 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 			ly.ColSpan = int(iv)
 		}
 	},
+	"grid-column-start": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridColStart = par.(*Layout).GridColStart
+			} else if init {
+				ly.GridColStart = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.GridColStart = int(iv)
+		}
+	},
+	"grid-row-start": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridRowStart = par.(*Layout).GridRowStart
+			} else if init {
+				ly.GridRowStart = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.GridRowStart = int(iv)
+		}
+	},
+	"grid-column-end": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridColEnd = par.(*Layout).GridColEnd
+			} else if init {
+				ly.GridColEnd = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.GridColEnd = int(iv)
+		}
+	},
+	"grid-column": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridColStart, ly.GridColEnd = par.(*Layout).GridColStart, par.(*Layout).GridColEnd
+			} else if init {
+				ly.GridColStart, ly.GridColEnd = 0, 0
+			}
+			return
+		}
+		if str, ok := val.(string); ok { // start / end
+			lines := strings.Split(str, "/")
+			if len(lines) > 2 {
+				StyleSetError(key, val)
+				return
+			}
+			st, ok := kit.ToInt(strings.TrimSpace(lines[0]))
+			if !ok {
+				StyleSetError(key, val)
+				return
+			}
+			ed := int64(0)
+			if len(lines) == 2 {
+				if ed, ok = kit.ToInt(strings.TrimSpace(lines[1])); !ok {
+					StyleSetError(key, val)
+					return
+				}
+			}
+			ly.GridColStart, ly.GridColEnd = int(st), int(ed)
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.GridColStart, ly.GridColEnd = int(iv), 0
+		}
+	},
+	"grid-area": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.GridArea = par.(*Layout).GridArea
+			} else if init {
+				ly.GridArea = ""
+			}
+			return
+		}
+		ly.GridArea = kit.ToString(val)
+	},
 	"scrollbar-width": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
 		ly := obj.(*Layout)
 		if inh, init := StyleInhInit(val, par); inh || init {
@@ -460,6 +605,85 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 		}
 		ly.ScrollBarWidth.SetIFace(val, key)
 	},
+	"order": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.Order = par.(*Layout).Order
+			} else if init {
+				ly.Order = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToInt(val); ok {
+			ly.Order = int(iv)
+		}
+	},
+	"region": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.Region = par.(*Layout).Region
+			} else if init {
+				ly.Region = RegionCenter
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case string:
+			kit.Enums.SetAnyEnumIfaceFromString(&ly.Region, vt)
+		case Region:
+			ly.Region = vt
+		default:
+			if iv, ok := kit.ToInt(val); ok {
+				ly.Region = Region(iv)
+			} else {
+				StyleSetError(key, val)
+			}
+		}
+	},
+	"aspect-ratio": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.AspectRatio = par.(*Layout).AspectRatio
+			} else if init {
+				ly.AspectRatio = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToFloat32(val); ok {
+			ly.AspectRatio = iv
+		}
+	},
+	"flex-grow": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.FlexGrow = par.(*Layout).FlexGrow
+			} else if init {
+				ly.FlexGrow = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToFloat32(val); ok {
+			ly.FlexGrow = iv
+		}
+	},
+	"flex-shrink": func(obj interface{}, key string, val interface{}, par interface{}, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.FlexShrink = par.(*Layout).FlexShrink
+			} else if init {
+				ly.FlexShrink = 0
+			}
+			return
+		}
+		if iv, ok := kit.ToFloat32(val); ok {
+			ly.FlexShrink = iv
+		}
+	},
 }
 
 /////////////////////////////////////////////////////////////////////////////////
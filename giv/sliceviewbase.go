@@ -1010,6 +1010,7 @@ func (sv *SliceViewBase) Render2D() {
 			}
 		}
 	}
+	sv.RenderShadow()
 	if sv.PushBounds() {
 		if !sv.InFullRebuild && sv.SliceGridNeedsLayout() {
 			// note: we are outside of slice grid and thus cannot do proper layout during Layout2D
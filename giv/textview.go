@@ -4818,6 +4818,7 @@ func (tv *TextView) Render2D() {
 		} else {
 			tv.Sty = tv.StateStyles[TextViewActive]
 		}
+		tv.LayState.BoxSpcOk = false // style replaced
 
 		tv.RenderAllLinesInBounds()
 		if tv.ScrollToCursorOnRender {
@@ -2306,6 +2306,7 @@ func (tv *TreeView) Render2D() {
 			} else {
 				tv.Sty = tv.StateStyles[TreeViewActive]
 			}
+			tv.LayState.BoxSpcOk = false // style replaced
 			tv.ConfigPartsIfNeeded()
 			tv.This().(gi.Node2D).ConnectEvents2D()
 
@@ -6,6 +6,7 @@ package vkos
 
 import (
 	"image"
+	"math"
 	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -14,6 +15,7 @@ import (
 	"github.com/goki/gi/oswin/key"
 	"github.com/goki/gi/oswin/mimedata"
 	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/mat32"
 )
 
 var (
@@ -170,6 +172,8 @@ func (w *windowImpl) mouseButtonEvent(gw *glfw.Window, button glfw.MouseButton,
 
 func (w *windowImpl) scrollEvent(gw *glfw.Window, xoff, yoff float64) {
 	mods := lastMods
+	// fractional offsets come from continuous devices such as trackpads
+	precise := xoff != math.Trunc(xoff) || yoff != math.Trunc(yoff)
 	if theApp.Platform() == oswin.MacOS {
 		xoff *= float64(mouse.ScrollWheelSpeed)
 		yoff *= float64(mouse.ScrollWheelSpeed)
@@ -184,7 +188,9 @@ func (w *windowImpl) scrollEvent(gw *glfw.Window, xoff, yoff float64) {
 			Action:    mouse.Scroll,
 			Modifiers: mods,
 		},
-		Delta: image.Point{int(-xoff), int(-yoff)},
+		Delta:        image.Point{int(-xoff), int(-yoff)},
+		Precise:      precise,
+		PreciseDelta: mat32.NewVec2(float32(-xoff), float32(-yoff)),
 	}
 	event.Init()
 	w.Send(event)
@@ -22,6 +22,7 @@ import (
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/key"
 	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
 )
 
 // DoubleClickMSec is the maximum time interval in msec between button press
@@ -142,6 +143,16 @@ type ScrollEvent struct {
 
 	// Delta is the amount of scrolling in each axis
 	Delta image.Point
+
+	// Precise is true for continuous, high-resolution scrolling devices
+	// such as trackpads, which deliver small sub-line deltas -- PreciseDelta
+	// then has the exact amount of scrolling in pixels, and Delta is the
+	// truncated integer version of it
+	Precise bool
+
+	// PreciseDelta is the exact amount of scrolling in each axis in pixels,
+	// for Precise scrolling
+	PreciseDelta mat32.Vec2
 }
 
 // NonZeroDelta attempts to find a non-zero delta -- often only get Y
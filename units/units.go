@@ -98,6 +98,11 @@ const (
 	// Dot = actual real display pixels -- generally only use internally
 	Dot
 
+	// Fr = fraction of the remaining space -- used for flexible sizing in
+	// layouts, where the value is a relative stretch weight -- converts to
+	// 0 dots, as the size is only determined during layout
+	Fr
+
 	UnitsN
 )
 
@@ -127,6 +132,7 @@ var UnitNames = [...]string{
 	Pc:   "pc",
 	Pt:   "pt",
 	Dot:  "dot",
+	Fr:   "fr",
 }
 
 // Context specifies everything about the current context necessary for converting the number
@@ -227,9 +233,9 @@ func (uc *Context) ToDotsFactor(un Units) float32 {
 	case Vh:
 		return 0.01 * uc.VpH
 	case Vmin:
-		return kit.Min32(uc.VpW, uc.VpH)
+		return 0.01 * kit.Min32(uc.VpW, uc.VpH)
 	case Vmax:
-		return kit.Max32(uc.VpW, uc.VpH)
+		return 0.01 * kit.Max32(uc.VpW, uc.VpH)
 	case Cm:
 		return uc.DPI / CmPerInch
 	case Mm:
@@ -248,6 +254,8 @@ func (uc *Context) ToDotsFactor(un Units) float32 {
 		return uc.DPI / DpPerInch
 	case Dot:
 		return 1.0
+	case Fr:
+		return 0
 	}
 	return uc.DPI
 }
@@ -328,6 +336,11 @@ func NewDot(val float32) Value {
 	return Value{val, Dot, 0.0}
 }
 
+// NewFr creates a new Fr value
+func NewFr(val float32) Value {
+	return Value{val, Fr, 0.0}
+}
+
 // Set sets value and units of an existing value
 func (v *Value) Set(val float32, un Units) {
 	v.Val = val
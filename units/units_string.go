@@ -31,12 +31,13 @@ func _() {
 	_ = x[Pc-15]
 	_ = x[Pt-16]
 	_ = x[Dot-17]
-	_ = x[UnitsN-18]
+	_ = x[Fr-18]
+	_ = x[UnitsN-19]
 }
 
-const _Units_name = "PxDpPctRemEmExChVwVhVminVmaxCmMmQInPcPtDotUnitsN"
+const _Units_name = "PxDpPctRemEmExChVwVhVminVmaxCmMmQInPcPtDotFrUnitsN"
 
-var _Units_index = [...]uint8{0, 2, 4, 7, 10, 12, 14, 16, 18, 20, 24, 28, 30, 32, 33, 35, 37, 39, 42, 48}
+var _Units_index = [...]uint8{0, 2, 4, 7, 10, 12, 14, 16, 18, 20, 24, 28, 30, 32, 33, 35, 37, 39, 42, 44, 50}
 
 func (i Units) String() string {
 	if i < 0 || i >= Units(len(_Units_index)-1) {
@@ -31,3 +31,36 @@ func TestValCvt(t *testing.T) {
 		t.Errorf("strings don't match: %v != %v\n", s1, s2)
 	}
 }
+
+func TestFr(t *testing.T) {
+	var ctxt Context
+	ctxt.Defaults()
+	v := StringToValue("2fr")
+	if v.Val != 2 || v.Un != Fr {
+		t.Errorf("2fr parsed as: %v", v)
+	}
+	if d := v.ToDots(&ctxt); d != 0 {
+		t.Errorf("fr dots: %v, expected 0", d)
+	}
+}
+
+func TestViewportUnits(t *testing.T) {
+	var ctxt Context
+	ctxt.Defaults()
+	ctxt.SetSizes(400, 300, 0, 0)
+	tests := []struct {
+		val string
+		exp float32
+	}{
+		{"50vw", 200},
+		{"50vh", 150},
+		{"10vmin", 30},
+		{"10vmax", 40},
+	}
+	for _, tt := range tests {
+		v := StringToValue(tt.val)
+		if d := v.ToDots(&ctxt); d != tt.exp {
+			t.Errorf("%v dots: %v, expected %v", tt.val, d, tt.exp)
+		}
+	}
+}